@@ -1,7 +1,102 @@
 package actions
 
-import "github.com/brutalzinn/focus-helper/config"
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/brutalzinn/focus-helper/config"
+)
+
+// ErrUserIdle é a causa do cancelamento dos alertas quando o usuário fica ocioso.
+var ErrUserIdle = errors.New("usuário ocioso")
+
+// Event descreve o disparo de um nível de alerta para as ações.
+type Event struct {
+	Level       config.AlertLevel
+	State       *config.HyperfocusState
+	Repeat      int                   // ciclo de repetição atual, a partir de 1
+	MaxRepeats  int                   // teto de ciclos de repetição imposto pelo limitador (0 = sem teto)
+	Acknowledge func()                // encerra o alerta em andamento, se definido
+	Snooze      func(d time.Duration) // encerra o alerta e adia os próximos por d, se definido
+
+	SessionDuration     time.Duration // uso acumulado que disparou o alerta
+	LastWellbeingAnswer string        // última resposta às perguntas de bem-estar
+
+	Record       func(ActionResult)    // grava o resultado de uma ação no histórico do alerta, se definido
+	RequireBreak func(d time.Duration) // exige uma pausa mínima antes de a sessão ser zerada, se definido
+
+	BreakConfirmed <-chan struct{} // fechado quando o monitor de atividade confirma uma pausa suficiente
+	Shutdown       <-chan struct{} // fechado no encerramento do Focus Helper
+
+	waitAll bool // aguarda todas as ações do ciclo, não só as de áudio
+}
+
+// ActionResult é o resultado de uma ação registrado no histórico do alerta.
+type ActionResult struct {
+	Type     config.ActionType
+	ExitCode int
+	Output   string
+	Err      error
+}
+
+func (e Event) record(result ActionResult) {
+	if e.Record != nil {
+		e.Record(result)
+	}
+}
 
 type Action interface {
-	Execute(level config.AlertLevel) error
+	Execute(ctx context.Context, event Event) error
+}
+
+// Describer é implementado pelas ações que sabem descrever o que fariam, sem
+// efeitos colaterais, para o modo --dry-run do comando test-alert.
+type Describer interface {
+	Describe(event Event) string
+}
+
+// TemplateData são os dados do evento expostos aos templates das ações.
+type TemplateData struct {
+	Level           string        `json:"level"`
+	SessionDuration time.Duration `json:"-"`
+	SessionMinutes  int           `json:"session_minutes"`
+	Repeat          int           `json:"repeat"`
+	Profile         string        `json:"profile"`
+	Message         string        `json:"message"`
+	Task            string        `json:"task,omitempty"`
+	Timestamp       time.Time     `json:"timestamp"`
+}
+
+// TemplateData monta os dados do evento para templates.
+func (e Event) TemplateData() TemplateData {
+	data := TemplateData{
+		Level:           e.Level.Level,
+		SessionDuration: e.SessionDuration.Round(time.Second),
+		SessionMinutes:  int(e.SessionDuration.Minutes()),
+		Repeat:          e.Repeat,
+		Profile:         config.AppConfig.Profile,
+		Message:         e.Message(),
+		Timestamp:       time.Now(),
+	}
+	if e.State != nil {
+		data.Task = e.State.Task
+	}
+	return data
+}
+
+// Message retorna o texto configurado do alerta: a primeira instrução ATC ou
+// mensagem de popup da sequência do nível.
+func (e Event) Message() string {
+	for _, step := range e.Level.Sequence() {
+		for _, actionCfg := range step.Actions {
+			if actionCfg.LlamaPrompt != "" {
+				return actionCfg.LlamaPrompt
+			}
+			if actionCfg.PopupMessage != "" {
+				return actionCfg.PopupMessage
+			}
+		}
+	}
+	return ""
 }
@@ -1,6 +1,8 @@
 package actions
 
 import (
+	"context"
+	"fmt"
 	"log"
 
 	"github.com/brutalzinn/focus-helper/audio"
@@ -16,22 +18,49 @@ type ATCAction struct {
 	Multiplier       float64
 }
 
-func (a *ATCAction) Execute(alert config.AlertLevel) error {
+// atcFallbackMessage é transmitida quando o LLM não responde.
+const atcFallbackMessage = "Alfa-Um, aqui é a Torre. Ação imediata requerida."
+
+func (a *ATCAction) Execute(ctx context.Context, event Event) error {
 	log.Println("  -> Executando ATCAction")
-	// Gerar o texto com Llama
-
-	prompt := integrations.NewATCPromptManager()
-	finalPrompt := prompt.FormatPromptWithLevel(alert.Level, a.LlamaPrompt)
-	alertText, err := integrations.GenerateTextWithLlama(config.AppConfig.Llama.Model, finalPrompt)
-	if err != nil {
-		log.Printf("Erro ao gerar texto ATC com Llama, usando fallback: %v", err)
-		alertText = "Alfa-Um, aqui é a Torre. Ação imediata requerida."
+	prompt := a.prompt(event)
+
+	alertText, ok := takePrerendered(prompt)
+	if ok {
+		log.Println("  -> Usando transmissão ATC pré-renderizada.")
+		a.prerenderLater(prompt)
+	} else {
+		// Gerar o texto com Llama
+		var err error
+		alertText, err = integrations.GenerateTextWithLlama(ctx, config.AppConfig.Llama.Model, prompt)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			log.Printf("Erro ao gerar texto ATC com Llama, usando fallback: %v", err)
+			alertText = atcFallbackMessage
+		}
 	}
 
 	return audio.PlayRadioSimulation(
+		ctx,
 		alertText,
 		a.VoiceVolume,
 		a.BackgroundVolume,
 		a.BackgroundFile,
 	)
 }
+
+// prompt monta o prompt enviado ao LLM, com o nível e a tarefa do plano de voo.
+func (a *ATCAction) prompt(event Event) string {
+	task := ""
+	if event.State != nil {
+		task = event.State.Task
+	}
+	return integrations.NewATCPromptManager().FormatPromptWithLevel(event.Level.Level, task, a.LlamaPrompt)
+}
+
+func (a *ATCAction) Describe(event Event) string {
+	return fmt.Sprintf("prompt LLM (%s):\n%s\nfundo %s, volume da voz %.1f, volume do fundo %.1f",
+		config.AppConfig.Llama.Model, a.prompt(event), audio.AssetPath(a.BackgroundFile), a.VoiceVolume, a.BackgroundVolume)
+}
@@ -0,0 +1,44 @@
+package actions
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"time"
+
+	"github.com/brutalzinn/focus-helper/config"
+)
+
+// randFloat sorteia a chance aleatória das ações; os testes o substituem.
+var randFloat = rand.Float64
+
+// firstRepeat retorna o primeiro ciclo de repetição em que a ação pode rodar.
+// Ações que não são de áudio rodam apenas nesse ciclo.
+func firstRepeat(actionCfg config.ActionConfig) int {
+	if actionCfg.Conditions.MinRepeat > 1 {
+		return actionCfg.Conditions.MinRepeat
+	}
+	return 1
+}
+
+// shouldRun avalia as condições e a chance aleatória de uma ação. Quando a
+// ação não deve rodar, retorna o motivo para o log.
+func shouldRun(actionCfg config.ActionConfig, event Event, now time.Time) (bool, string) {
+	cond := actionCfg.Conditions
+	if event.Repeat < cond.MinRepeat {
+		return false, fmt.Sprintf("ciclo %d abaixo do mínimo %d", event.Repeat, cond.MinRepeat)
+	}
+	if cond.OutsideQuietHours && config.AppConfig.QuietHours.Contains(now) {
+		return false, "horário silencioso"
+	}
+	if cond.LastWellbeingAnswer != "" && cond.LastWellbeingAnswer != event.LastWellbeingAnswer {
+		return false, fmt.Sprintf("última resposta de bem-estar foi %q", event.LastWellbeingAnswer)
+	}
+	if len(cond.Weekdays) > 0 && !slices.Contains(cond.Weekdays, now.Weekday()) {
+		return false, fmt.Sprintf("dia da semana %s não permitido", now.Weekday())
+	}
+	if actionCfg.RandomChance > 0 && randFloat() >= actionCfg.RandomChance {
+		return false, fmt.Sprintf("chance aleatória de %.0f%% não sorteada", actionCfg.RandomChance*100)
+	}
+	return true, ""
+}
@@ -0,0 +1,59 @@
+package actions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/brutalzinn/focus-helper/config"
+)
+
+func TestShouldRun(t *testing.T) {
+	prevQuiet, prevRand := config.AppConfig.QuietHours, randFloat
+	t.Cleanup(func() { config.AppConfig.QuietHours, randFloat = prevQuiet, prevRand })
+	config.AppConfig.QuietHours = config.QuietHoursConfig{Start: 22 * time.Hour, End: 7 * time.Hour}
+
+	// 19/10/2026 é uma segunda-feira.
+	monday := time.Date(2026, 10, 19, 14, 0, 0, 0, time.Local)
+	night := time.Date(2026, 10, 19, 23, 30, 0, 0, time.Local)
+	tests := []struct {
+		name   string
+		action config.ActionConfig
+		event  Event
+		now    time.Time
+		draw   float64
+		want   bool
+	}{
+		{"sem condições", config.ActionConfig{}, Event{Repeat: 1}, monday, 0.99, true},
+		{"abaixo do ciclo mínimo", config.ActionConfig{Conditions: config.ActionConditions{MinRepeat: 2}}, Event{Repeat: 1}, monday, 0, false},
+		{"no ciclo mínimo", config.ActionConfig{Conditions: config.ActionConditions{MinRepeat: 2}}, Event{Repeat: 2}, monday, 0, true},
+		{"depois do ciclo mínimo", config.ActionConfig{Conditions: config.ActionConditions{MinRepeat: 2}}, Event{Repeat: 3}, monday, 0, true},
+		{"fora do horário silencioso", config.ActionConfig{Conditions: config.ActionConditions{OutsideQuietHours: true}}, Event{Repeat: 1}, monday, 0, true},
+		{"dentro do horário silencioso", config.ActionConfig{Conditions: config.ActionConditions{OutsideQuietHours: true}}, Event{Repeat: 1}, night, 0, false},
+		{"resposta de bem-estar igual", config.ActionConfig{Conditions: config.ActionConditions{LastWellbeingAnswer: "Não"}}, Event{Repeat: 1, LastWellbeingAnswer: "Não"}, monday, 0, true},
+		{"resposta de bem-estar diferente", config.ActionConfig{Conditions: config.ActionConditions{LastWellbeingAnswer: "Não"}}, Event{Repeat: 1, LastWellbeingAnswer: "Sim"}, monday, 0, false},
+		{"sem resposta de bem-estar", config.ActionConfig{Conditions: config.ActionConditions{LastWellbeingAnswer: "Não"}}, Event{Repeat: 1}, monday, 0, false},
+		{"dia da semana permitido", config.ActionConfig{Conditions: config.ActionConditions{Weekdays: []time.Weekday{time.Monday, time.Tuesday}}}, Event{Repeat: 1}, monday, 0, true},
+		{"dia da semana bloqueado", config.ActionConfig{Conditions: config.ActionConditions{Weekdays: []time.Weekday{time.Saturday, time.Sunday}}}, Event{Repeat: 1}, monday, 0, false},
+		{"chance zero roda sempre", config.ActionConfig{RandomChance: 0}, Event{Repeat: 1}, monday, 0.999, true},
+		{"chance um roda sempre", config.ActionConfig{RandomChance: 1}, Event{Repeat: 1}, monday, 0.999, true},
+		{"sorteio abaixo da chance", config.ActionConfig{RandomChance: 0.3}, Event{Repeat: 1}, monday, 0.29, true},
+		{"sorteio igual à chance", config.ActionConfig{RandomChance: 0.3}, Event{Repeat: 1}, monday, 0.3, false},
+		{"sorteio acima da chance", config.ActionConfig{RandomChance: 0.3}, Event{Repeat: 1}, monday, 0.9, false},
+	}
+	for _, tt := range tests {
+		draw := tt.draw
+		randFloat = func() float64 { return draw }
+		if got, reason := shouldRun(tt.action, tt.event, tt.now); got != tt.want {
+			t.Errorf("%s: shouldRun = %v (%s), esperado %v", tt.name, got, reason, tt.want)
+		}
+	}
+}
+
+func TestFirstRepeat(t *testing.T) {
+	for minRepeat, want := range map[int]int{0: 1, 1: 1, 2: 2, 3: 3} {
+		actionCfg := config.ActionConfig{Conditions: config.ActionConditions{MinRepeat: minRepeat}}
+		if got := firstRepeat(actionCfg); got != want {
+			t.Errorf("firstRepeat(MinRepeat %d) = %d, esperado %d", minRepeat, got, want)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package actions
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/brutalzinn/focus-helper/config"
+)
+
+// DryRun descreve em w o que o alerta faria, etapa por etapa, sem tocar
+// áudio, abrir janelas, iniciar plugins ou chamar serviços externos.
+func DryRun(w io.Writer, event Event) {
+	fmt.Fprintf(w, "Nível %s: %d ciclo(s) de repetição, sessão de %v\n",
+		event.Level.Level, repetitions(event), event.SessionDuration.Round(time.Second))
+	if event.State != nil && event.State.Task != "" {
+		fmt.Fprintf(w, "Plano de voo: %s\n", event.State.Task)
+	}
+	now := time.Now()
+	for n, step := range event.Level.Sequence() {
+		fmt.Fprintf(w, "\nEtapa %d", n+1)
+		if step.Delay > 0 {
+			fmt.Fprintf(w, " (após %v)", step.Delay)
+		}
+		if step.WaitPrevious {
+			fmt.Fprint(w, " (aguarda a etapa anterior)")
+		}
+		fmt.Fprintln(w)
+		for _, actionCfg := range step.Actions {
+			fmt.Fprintf(w, "  - %s%s\n", actionCfg.Type, dryRunConditions(actionCfg, event, now))
+			action, err := NewActionFromConfig(event.Level, actionCfg)
+			if errors.Is(err, errUnknownActionType) {
+				fmt.Fprintln(w, "      (ação de plugin ou tipo desconhecido; plugins não são iniciados no --dry-run)")
+				continue
+			}
+			if err != nil {
+				fmt.Fprintf(w, "      erro: %v\n", err)
+				continue
+			}
+			describer, ok := action.(Describer)
+			if !ok {
+				fmt.Fprintln(w, "      (ação sem descrição)")
+				continue
+			}
+			for _, line := range strings.Split(describer.Describe(event), "\n") {
+				fmt.Fprintf(w, "      %s\n", line)
+			}
+		}
+	}
+}
+
+// dryRunConditions resume quando a ação roda e se as condições bloqueiam agora.
+func dryRunConditions(actionCfg config.ActionConfig, event Event, now time.Time) string {
+	var notes []string
+	if actionCfg.Type == config.ActionATC {
+		notes = append(notes, "repete a cada ciclo")
+	}
+	if first := firstRepeat(actionCfg); first > 1 {
+		notes = append(notes, fmt.Sprintf("a partir do ciclo %d", first))
+	}
+	if actionCfg.RandomChance > 0 {
+		notes = append(notes, fmt.Sprintf("chance de %.0f%%", actionCfg.RandomChance*100))
+	}
+	// Avalia as condições no primeiro ciclo em que a ação roda, sem o sorteio.
+	probe := actionCfg
+	probe.RandomChance = 0
+	event.Repeat = firstRepeat(actionCfg)
+	if ok, reason := shouldRun(probe, event, now); !ok {
+		notes = append(notes, "bloqueada agora: "+reason)
+	}
+	if len(notes) == 0 {
+		return ""
+	}
+	return " [" + strings.Join(notes, "; ") + "]"
+}
@@ -0,0 +1,26 @@
+package actions
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/brutalzinn/focus-helper/config"
+)
+
+func TestDryRunDoesNotResolvePlugins(t *testing.T) {
+	level := config.AlertLevel{
+		Level: "HIGH",
+		Actions: []config.ActionConfig{
+			{Type: config.ActionPopup, PopupTitle: "Pausa", PopupMessage: "Hora de parar"},
+			{Type: "CUSTOM_PLUGIN"},
+		},
+	}
+	var out strings.Builder
+	DryRun(&out, Event{Level: level})
+	if !strings.Contains(out.String(), "CUSTOM_PLUGIN") || !strings.Contains(out.String(), "plugins não são iniciados") {
+		t.Errorf("saída não lista a ação de plugin sem iniciá-la:\n%s", out.String())
+	}
+	if strings.Contains(out.String(), "erro:") {
+		t.Errorf("ação de plugin tratada como erro:\n%s", out.String())
+	}
+}
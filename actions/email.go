@@ -0,0 +1,80 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/brutalzinn/focus-helper/config"
+	"github.com/brutalzinn/focus-helper/integrations"
+)
+
+const (
+	defaultEmailSubject = "Focus Helper: alerta {{.Level}}"
+	defaultEmailBody    = `{{.Message}}
+
+Nível: {{.Level}}
+Sessão: {{.SessionMinutes}} minutos
+{{if .Task}}Plano de voo: {{.Task}}
+{{end}}Horário: {{.Timestamp.Format "02/01/2006 15:04"}}
+`
+)
+
+type EmailAction struct {
+	Config config.EmailConfig
+}
+
+func (a *EmailAction) Execute(ctx context.Context, event Event) error {
+	log.Printf("  -> Executando EmailAction para %v", a.Config.To)
+	email, err := a.render(event)
+	if err != nil {
+		return err
+	}
+	err = SendEmail(ctx, email)
+	if err != nil {
+		event.record(ActionResult{Type: config.ActionEmail, Err: err})
+	}
+	return err
+}
+
+func (a *EmailAction) Describe(event Event) string {
+	email, err := a.render(event)
+	if err != nil {
+		return err.Error()
+	}
+	return fmt.Sprintf("para %v via %s:%d\nassunto: %s\n%s", email.To, config.AppConfig.SMTP.Host, config.AppConfig.SMTP.Port, email.Subject, email.Body)
+}
+
+// render aplica os dados do evento ao assunto e ao corpo.
+func (a *EmailAction) render(event Event) (integrations.Email, error) {
+	email := integrations.Email{To: a.Config.To}
+	data := event.TemplateData()
+	subject, body := a.Config.Subject, a.Config.Body
+	if subject == "" {
+		subject = defaultEmailSubject
+	}
+	if body == "" {
+		body = defaultEmailBody
+	}
+	var err error
+	if email.Subject, err = renderTemplate("email_subject", subject, data); err != nil {
+		return email, fmt.Errorf("erro ao renderizar assunto do e-mail: %w", err)
+	}
+	if email.Body, err = renderTemplate("email_body", body, data); err != nil {
+		return email, fmt.Errorf("erro ao renderizar corpo do e-mail: %w", err)
+	}
+	return email, nil
+}
+
+// SendEmail envia o e-mail pelo servidor SMTP configurado.
+func SendEmail(ctx context.Context, email integrations.Email) error {
+	smtp := config.AppConfig.SMTP
+	return integrations.SendEmail(ctx, integrations.SMTPServer{
+		Host:     smtp.Host,
+		Port:     smtp.Port,
+		Username: smtp.Username,
+		Password: smtp.Password,
+		From:     smtp.From,
+		StartTLS: smtp.StartTLS,
+	}, email)
+}
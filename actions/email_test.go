@@ -0,0 +1,93 @@
+package actions
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"regexp"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/brutalzinn/focus-helper/config"
+	"github.com/brutalzinn/focus-helper/integrations/smtptest"
+)
+
+func TestEmailActionRendersAlert(t *testing.T) {
+	event := Event{
+		Level: config.AlertLevel{Level: "HIGH", Actions: []config.ActionConfig{
+			{Type: config.ActionPopup, PopupMessage: "Faça uma pausa"},
+		}},
+		State:           &config.HyperfocusState{Task: "relatório"},
+		Repeat:          2,
+		SessionDuration: 95 * time.Minute,
+	}
+	tests := []struct {
+		name        string
+		config      config.EmailConfig
+		wantSubject string
+		wantBody    *regexp.Regexp
+	}{
+		{
+			name:        "templates padrão",
+			config:      config.EmailConfig{To: []string{"piloto@exemplo.com"}},
+			wantSubject: "Focus Helper: alerta HIGH",
+			wantBody:    regexp.MustCompile(`^Faça uma pausa\n\nNível: HIGH\nSessão: 95 minutos\nPlano de voo: relatório\nHorário: \d{2}/\d{2}/\d{4} \d{2}:\d{2}\n$`),
+		},
+		{
+			name: "templates configurados",
+			config: config.EmailConfig{
+				To:      []string{"piloto@exemplo.com", "torre@exemplo.com"},
+				Subject: "[{{.Profile}}] {{.Level}} há {{.SessionMinutes}} min",
+				Body:    "Ciclo {{.Repeat}} de {{.Task}}",
+			},
+			wantSubject: "[teste] HIGH há 95 min",
+			wantBody:    regexp.MustCompile(`^Ciclo 2 de relatório\n$`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := smtptest.NewServer(t)
+			config.AppConfig.Profile = "teste"
+			config.AppConfig.SMTP = config.SMTPConfig{Host: srv.Host, Port: srv.Port, From: "focus@exemplo.com"}
+
+			action := &EmailAction{Config: tt.config}
+			if err := action.Execute(context.Background(), event); err != nil {
+				t.Fatal(err)
+			}
+			msg := srv.Next(t)
+			if !slices.Equal(msg.To, tt.config.To) {
+				t.Errorf("destinatários %v, esperado %v", msg.To, tt.config.To)
+			}
+			parsed, err := msg.Parse()
+			if err != nil {
+				t.Fatal(err)
+			}
+			subject, _ := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+			if subject != tt.wantSubject {
+				t.Errorf("assunto %q, esperado %q", subject, tt.wantSubject)
+			}
+			body, _ := io.ReadAll(quotedprintable.NewReader(parsed.Body))
+			if !tt.wantBody.Match(body) {
+				t.Errorf("corpo %q não corresponde a %s", body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestEmailActionRecordsFailure(t *testing.T) {
+	srv := smtptest.NewServer(t)
+	srv.Close()
+	config.AppConfig.SMTP = config.SMTPConfig{Host: srv.Host, Port: srv.Port, From: "focus@exemplo.com"}
+
+	var results []ActionResult
+	event := Event{Level: config.AlertLevel{Level: "HIGH"}, Record: func(r ActionResult) { results = append(results, r) }}
+	action := &EmailAction{Config: config.EmailConfig{To: []string{"piloto@exemplo.com"}}}
+	if err := action.Execute(context.Background(), event); err == nil {
+		t.Fatal("envio sem servidor deveria falhar")
+	}
+	if len(results) != 1 || results[0].Type != config.ActionEmail || results[0].Err == nil {
+		t.Errorf("resultados registrados = %+v", results)
+	}
+}
@@ -0,0 +1,94 @@
+package actions
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/brutalzinn/focus-helper/config"
+)
+
+const maxExecOutput = 4096
+
+type ExecAction struct {
+	Config config.ExecConfig
+}
+
+// Execute roda o comando configurado com o evento nas variáveis FOCUS_* e em
+// JSON na entrada padrão. O processo é encerrado no timeout ou quando o
+// contexto do alerta é cancelado.
+func (a *ExecAction) Execute(ctx context.Context, event Event) error {
+	log.Printf("  -> Executando ExecAction: %v", a.Config.Command)
+	timeout := a.Config.Timeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	data := event.TemplateData()
+	stdin, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	var output bytes.Buffer
+	cmd := exec.CommandContext(ctx, a.Config.Command[0], a.Config.Command[1:]...)
+	cmd.Dir = a.Config.Dir
+	cmd.Env = append(os.Environ(), execEnv(data)...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	cmd.WaitDelay = 2 * time.Second
+	killProcessGroup(cmd)
+
+	err = cmd.Run()
+	result := ActionResult{Type: config.ActionExec, Output: truncateOutput(output.String()), Err: err}
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+	default:
+		result.ExitCode = -1
+	}
+	if ctx.Err() != nil {
+		result.Err = fmt.Errorf("comando interrompido: %w", context.Cause(ctx))
+	}
+	event.record(result)
+	if result.Err != nil {
+		return fmt.Errorf("erro ao executar %v (status %d): %w", a.Config.Command, result.ExitCode, result.Err)
+	}
+	log.Printf("  -> Comando %v concluído.", a.Config.Command)
+	return nil
+}
+
+func execEnv(data TemplateData) []string {
+	return []string{
+		"FOCUS_LEVEL=" + data.Level,
+		"FOCUS_DURATION=" + strconv.Itoa(int(data.SessionDuration.Seconds())),
+		"FOCUS_SESSION_MINUTES=" + strconv.Itoa(data.SessionMinutes),
+		"FOCUS_REPEAT=" + strconv.Itoa(data.Repeat),
+		"FOCUS_PROFILE=" + data.Profile,
+		"FOCUS_MESSAGE=" + data.Message,
+		"FOCUS_TASK=" + data.Task,
+	}
+}
+
+func truncateOutput(output string) string {
+	if len(output) <= maxExecOutput {
+		return output
+	}
+	return output[:maxExecOutput] + "\n[saída truncada]"
+}
+
+func (a *ExecAction) Describe(event Event) string {
+	stdin, _ := json.Marshal(event.TemplateData())
+	return fmt.Sprintf("comando %q (dir %q, timeout %v)\nentrada padrão: %s", a.Config.Command, a.Config.Dir, a.Config.Timeout, stdin)
+}
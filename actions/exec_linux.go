@@ -0,0 +1,22 @@
+package actions
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// killProcessGroup põe o comando num grupo de processos próprio e faz o
+// cancelamento encerrar o grupo inteiro, para que os filhos de um `sh -c`
+// não continuem rodando.
+func killProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		err := syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+		if errors.Is(err, syscall.ESRCH) {
+			return os.ErrProcessDone
+		}
+		return err
+	}
+}
@@ -0,0 +1,53 @@
+package actions
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brutalzinn/focus-helper/config"
+)
+
+// alive indica se o processo ainda existe e não é um zumbi esperando o pai.
+func alive(pid int) bool {
+	stat, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return false
+	}
+	fields := strings.Fields(string(stat[strings.LastIndexByte(string(stat), ')')+1:]))
+	return fields[0] != "Z"
+}
+
+func TestExecActionKillsGrandchildren(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh não encontrado")
+	}
+	pidFile := filepath.Join(t.TempDir(), "neto.pid")
+	action := &ExecAction{Config: config.ExecConfig{
+		Command: []string{"sh", "-c", `sleep 30 & echo $! > "$0"; wait`, pidFile},
+		Timeout: 200 * time.Millisecond,
+	}}
+	if err := action.Execute(context.Background(), Event{}); err == nil {
+		t.Fatal("esperado erro de timeout")
+	}
+	data, err := os.ReadFile(pidFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for alive(pid) {
+		if time.Now().After(deadline) {
+			t.Fatalf("o processo neto %d continua rodando após o timeout", pid)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
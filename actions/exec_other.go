@@ -0,0 +1,7 @@
+//go:build !linux
+
+package actions
+
+import "os/exec"
+
+func killProcessGroup(cmd *exec.Cmd) {}
@@ -0,0 +1,59 @@
+package actions
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brutalzinn/focus-helper/config"
+)
+
+func TestExecAction(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh não encontrado")
+	}
+	event := Event{
+		Level:           config.AlertLevel{Level: "HIGH"},
+		State:           &config.HyperfocusState{Task: "relatório"},
+		SessionDuration: 95 * time.Minute,
+	}
+	tests := []struct {
+		name         string
+		command      string
+		timeout      time.Duration
+		wantOutput   string
+		wantExitCode int
+		wantErr      bool
+	}{
+		{"variáveis e entrada padrão", `echo "$FOCUS_LEVEL $FOCUS_SESSION_MINUTES $FOCUS_TASK"; grep -o '"level":"HIGH"'`, 0, "HIGH 95 relatório\n\"level\":\"HIGH\"\n", 0, false},
+		{"status de saída", "echo falhou >&2; exit 3", 0, "falhou\n", 3, true},
+		{"timeout", "sleep 30", 100 * time.Millisecond, "", -1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var results []ActionResult
+			ev := event
+			ev.Record = func(r ActionResult) { results = append(results, r) }
+			action := &ExecAction{Config: config.ExecConfig{Command: []string{"sh", "-c", tt.command}, Timeout: tt.timeout}}
+			start := time.Now()
+			err := action.Execute(context.Background(), ev)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Execute() = %v, esperado erro: %v", err, tt.wantErr)
+			}
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Errorf("Execute levou %v", elapsed)
+			}
+			if len(results) != 1 {
+				t.Fatalf("resultados registrados = %+v", results)
+			}
+			if got := results[0]; got.Output != tt.wantOutput || got.ExitCode != tt.wantExitCode {
+				t.Errorf("resultado %q status %d, esperado %q status %d", got.Output, got.ExitCode, tt.wantOutput, tt.wantExitCode)
+			}
+			if tt.timeout > 0 && !strings.Contains(results[0].Err.Error(), "interrompido") {
+				t.Errorf("erro %v, esperado a interrupção", results[0].Err)
+			}
+		})
+	}
+}
@@ -1,37 +1,126 @@
 package actions
 
 import (
+	"context"
 	"log"
+	"sync"
 	"time"
 
 	"github.com/brutalzinn/focus-helper/config"
 )
 
-func Execute(alert config.AlertLevel, hyperfocusState *config.HyperfocusState) {
+// Execute dispara a sequência de ações do nível de alerta. Cada ciclo de
+// repetição começa depois que as ações de áudio do ciclo anterior terminam, e
+// os ciclos param assim que o contexto é cancelado.
+func Execute(ctx context.Context, event Event) {
+	alert := event.Level
 	log.Printf("Executando ações para o nível de alerta: %s", alert.Level)
-	repetitions := int(alert.Multiplier)
-	if repetitions <= 0 {
-		repetitions = 1
-	}
+	repetitions := repetitions(event)
 	log.Printf("Nível de agressividade: %d repetições para ações de áudio/ATC.", repetitions)
 	for i := 0; i < repetitions; i++ {
+		if ctx.Err() != nil {
+			log.Printf("Alerta %s cancelado antes do ciclo %d: %v", alert.Level, i+1, context.Cause(ctx))
+			return
+		}
 		if repetitions > 1 {
 			log.Printf("--> Executando ciclo de ações %d de %d", i+1, repetitions)
 		}
-		for _, actionCfg := range alert.Actions {
+		event.Repeat = i + 1
+		runSequence(ctx, event)
+		if repetitions > 1 && i < repetitions-1 {
+			select {
+			case <-ctx.Done():
+				log.Printf("Alerta %s cancelado após o ciclo %d: %v", alert.Level, i+1, context.Cause(ctx))
+				return
+			case <-time.After(5 * time.Second):
+			}
+		}
+	}
+}
+
+// ExecuteAndWait é como Execute, mas também aguarda as ações que não são de
+// áudio, como popups, terminarem. Usado pelo comando test-alert.
+func ExecuteAndWait(ctx context.Context, event Event) {
+	event.waitAll = true
+	Execute(ctx, event)
+}
+
+// repetitions retorna quantos ciclos o alerta executa.
+func repetitions(event Event) int {
+	repetitions := int(event.Level.Multiplier)
+	if repetitions <= 0 {
+		repetitions = 1
+	}
+	if event.MaxRepeats > 0 && repetitions > event.MaxRepeats {
+		repetitions = event.MaxRepeats
+	}
+	return repetitions
+}
+
+// runSequence executa as etapas de um ciclo em ordem e retorna quando as ações
+// de áudio terminam. Ações que não são de áudio (como popups, que bloqueiam até
+// o usuário responder) não seguram o ciclo.
+func runSequence(ctx context.Context, event Event) {
+	var audioWG, allWG sync.WaitGroup
+	var previous chan struct{}
+	for n, step := range event.Level.Sequence() {
+		if step.WaitPrevious && previous != nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-previous:
+			}
+		}
+		if step.Delay > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(step.Delay):
+			}
+		}
+
+		var stepWG sync.WaitGroup
+		for _, actionCfg := range step.Actions {
 			isAudioAction := actionCfg.Type == config.ActionATC
-			if !isAudioAction && i > 0 {
+			if !isAudioAction && event.Repeat != firstRepeat(actionCfg) {
 				continue
 			}
-			action, err := NewActionFromConfig(alert, actionCfg)
+			if ok, reason := shouldRun(actionCfg, event, time.Now()); !ok {
+				log.Printf("  -> Ação %s ignorada: %s", actionCfg.Type, reason)
+				continue
+			}
+			action, err := NewActionFromConfig(event.Level, actionCfg)
 			if err != nil {
 				log.Printf("Erro ao criar ação: %v", err)
 				continue
 			}
-			go action.Execute(alert)
-		}
-		if repetitions > 1 && i < repetitions-1 {
-			time.Sleep(5 * time.Second)
+			holdsCycle := isAudioAction || actionCfg.Type == config.ActionSound
+			stepWG.Add(1)
+			allWG.Add(1)
+			if holdsCycle {
+				audioWG.Add(1)
+			}
+			go func() {
+				defer stepWG.Done()
+				defer allWG.Done()
+				if holdsCycle {
+					defer audioWG.Done()
+				}
+				if err := action.Execute(ctx, event); err != nil && ctx.Err() == nil {
+					log.Printf("Erro na ação %s (etapa %d): %v", actionCfg.Type, n+1, err)
+				}
+			}()
 		}
+
+		done := make(chan struct{})
+		go func() {
+			stepWG.Wait()
+			close(done)
+		}()
+		previous = done
+	}
+	audioWG.Wait()
+	if event.waitAll {
+		allWG.Wait()
 	}
 }
@@ -1,11 +1,17 @@
 package actions
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/brutalzinn/focus-helper/config"
+	"github.com/brutalzinn/focus-helper/plugins"
 )
 
+// errUnknownActionType indica um tipo que não é embutido nem atendido por um
+// plugin registrado.
+var errUnknownActionType = errors.New("tipo de ação desconhecido")
+
 func NewActionFromConfig(level config.AlertLevel, actionCfg config.ActionConfig) (Action, error) {
 
 	switch actionCfg.Type {
@@ -21,8 +27,9 @@ func NewActionFromConfig(level config.AlertLevel, actionCfg config.ActionConfig)
 
 	case config.ActionPopup:
 		return &PopupAction{
-			Title:   actionCfg.PopupTitle,
-			Message: actionCfg.PopupMessage,
+			Title:       actionCfg.PopupTitle,
+			Message:     actionCfg.PopupMessage,
+			Acknowledge: actionCfg.PopupAcknowledge,
 		}, nil
 
 	case config.ActionATC:
@@ -42,7 +49,51 @@ func NewActionFromConfig(level config.AlertLevel, actionCfg config.ActionConfig)
 			WebhookURL: actionCfg.HomeAssistant.WebhookURL,
 			Data:       "",
 		}, nil
+	case config.ActionWebhook:
+		if actionCfg.Webhook.URL == "" {
+			return nil, fmt.Errorf("ação %s sem URL configurada", actionCfg.Type)
+		}
+		return &WebhookAction{Config: actionCfg.Webhook}, nil
+	case config.ActionExec:
+		if len(actionCfg.Exec.Command) == 0 {
+			return nil, fmt.Errorf("ação %s sem comando configurado", actionCfg.Type)
+		}
+		return &ExecAction{Config: actionCfg.Exec}, nil
+	case config.ActionLockScreen:
+		return &LockScreenAction{Config: actionCfg.LockScreen}, nil
+	case config.ActionBreakOverlay:
+		return &BreakOverlayAction{Config: actionCfg.BreakOverlay}, nil
+	case config.ActionSuspendApps:
+		return newSuspendAppsAction(actionCfg.SuspendApps)
+	case config.ActionMQTT:
+		if actionCfg.MQTT.Topic == "" {
+			return nil, fmt.Errorf("ação %s sem tópico configurado", actionCfg.Type)
+		}
+		if actionCfg.MQTT.QoS > 2 {
+			return nil, fmt.Errorf("ação %s com QoS inválido: %d", actionCfg.Type, actionCfg.MQTT.QoS)
+		}
+		return &MQTTAction{Config: actionCfg.MQTT}, nil
+	case config.ActionNotify:
+		for _, button := range actionCfg.Notify.Buttons {
+			if button.Action != config.NotifyAcknowledge && button.Action != config.NotifySnooze {
+				return nil, fmt.Errorf("ação %s com botão inválido: %q", actionCfg.Type, button.Action)
+			}
+		}
+		return &NotifyAction{Config: actionCfg.Notify}, nil
+	case config.ActionEmail:
+		if len(actionCfg.Email.To) == 0 {
+			return nil, fmt.Errorf("ação %s sem destinatários configurados", actionCfg.Type)
+		}
+		return &EmailAction{Config: actionCfg.Email}, nil
+	case config.ActionNtfy:
+		if actionCfg.Ntfy.Topic == "" {
+			return nil, fmt.Errorf("ação %s sem tópico configurado", actionCfg.Type)
+		}
+		return &NtfyAction{Config: actionCfg.Ntfy}, nil
 	default:
-		return nil, fmt.Errorf("tipo de ação desconhecido: %s", actionCfg.Type)
+		if plugin := plugins.Lookup(string(actionCfg.Type)); plugin != nil {
+			return &PluginAction{Plugin: plugin, Type: actionCfg.Type, Config: actionCfg.Plugin}, nil
+		}
+		return nil, fmt.Errorf("%w: %s", errUnknownActionType, actionCfg.Type)
 	}
 }
@@ -1,9 +1,10 @@
 package actions
 
 import (
+	"context"
+	"fmt"
 	"log"
 
-	"github.com/brutalzinn/focus-helper/config"
 	"github.com/brutalzinn/focus-helper/integrations"
 )
 
@@ -12,7 +13,11 @@ type HomeAssistantAction struct {
 	Data       string
 }
 
-func (a *HomeAssistantAction) Execute(alert config.AlertLevel) error {
+func (a *HomeAssistantAction) Execute(ctx context.Context, event Event) error {
 	log.Println("  -> Executando HomeAssistantAction")
-	return integrations.TriggerHomeAssistant(a.WebhookURL, a.Data)
+	return integrations.TriggerHomeAssistant(ctx, a.WebhookURL, a.Data)
+}
+
+func (a *HomeAssistantAction) Describe(event Event) string {
+	return fmt.Sprintf("POST %s %s", a.WebhookURL, a.Data)
 }
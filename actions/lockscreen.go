@@ -0,0 +1,101 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os/exec"
+	"time"
+
+	"github.com/brutalzinn/focus-helper/activity"
+	"github.com/brutalzinn/focus-helper/config"
+	"github.com/brutalzinn/focus-helper/integrations"
+	"github.com/brutalzinn/focus-helper/notifications"
+)
+
+type LockScreenAction struct {
+	Config config.LockScreenConfig
+}
+
+// Execute avisa com uma contagem regressiva e então bloqueia a sessão. Se o
+// alerta for reconhecido ou cancelado durante a contagem, a tela não é bloqueada.
+func (a *LockScreenAction) Execute(ctx context.Context, event Event) error {
+	log.Println("  -> Executando LockScreenAction")
+	grace := a.Config.GracePeriod
+	if grace <= 0 {
+		grace = time.Minute
+	}
+	interval := a.Config.WarningInterval
+	if interval <= 0 {
+		interval = 15 * time.Second
+	}
+	if err := a.countdown(ctx, grace, interval); err != nil {
+		log.Printf("  -> Bloqueio de tela cancelado: %v", context.Cause(ctx))
+		return nil
+	}
+
+	err := integrations.LockSession(ctx, a.Config.BusAddress)
+	if err != nil && len(a.Config.FallbackCommand) > 0 {
+		log.Printf("Erro ao bloquear pelo logind, usando comando alternativo: %v", err)
+		cmd := exec.CommandContext(ctx, a.Config.FallbackCommand[0], a.Config.FallbackCommand[1:]...)
+		if out, cmdErr := cmd.CombinedOutput(); cmdErr != nil {
+			err = fmt.Errorf("comando alternativo %v falhou: %w (%s)", a.Config.FallbackCommand, cmdErr, out)
+		} else {
+			err = nil
+		}
+	}
+	event.record(ActionResult{Type: config.ActionLockScreen, Err: err})
+	if err != nil {
+		return err
+	}
+	log.Println("  -> Sessão bloqueada.")
+
+	if a.Config.EnforceMinBreak && event.RequireBreak != nil {
+		event.RequireBreak(a.minBreak(event))
+	}
+	return nil
+}
+
+func (a *LockScreenAction) countdown(ctx context.Context, grace, interval time.Duration) error {
+	deadline := time.Now().Add(grace)
+	for {
+		// O arredondamento é só para o aviso; a espera usa o tempo exato, senão
+		// um resto menor que meio segundo bloquearia a tela antes da hora.
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return nil
+		}
+		go notifications.ShowDesktopNotification("Bloqueio de tela", fmt.Sprintf("Torre: a tela será bloqueada em %v. Salve seu trabalho.", remaining.Round(time.Second)))
+		wait := interval
+		if remaining < wait {
+			wait = remaining
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (a *LockScreenAction) minBreak(event Event) time.Duration {
+	if a.Config.MinBreak > 0 {
+		return a.Config.MinBreak
+	}
+	return activity.RequiredBreak(event.SessionDuration, config.AppConfig.BreakPolicy)
+}
+
+func (a *LockScreenAction) Describe(event Event) string {
+	grace := a.Config.GracePeriod
+	if grace <= 0 {
+		grace = time.Minute
+	}
+	desc := fmt.Sprintf("bloqueia a sessão após contagem regressiva de %v", grace)
+	if len(a.Config.FallbackCommand) > 0 {
+		desc += fmt.Sprintf("\ncomando alternativo %q", a.Config.FallbackCommand)
+	}
+	if a.Config.EnforceMinBreak {
+		desc += fmt.Sprintf("\npausa mínima exigida: %v", a.minBreak(event).Round(time.Second))
+	}
+	return desc
+}
@@ -0,0 +1,176 @@
+package actions
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/brutalzinn/focus-helper/config"
+	"github.com/godbus/dbus/v5"
+)
+
+const fakeSessionPath = dbus.ObjectPath("/org/freedesktop/login1/session/_31")
+
+// fakeLogind simula o Manager e a Session do logind, registrando quando a
+// sessão foi bloqueada.
+type fakeLogind struct {
+	fail bool // Lock e LockSession respondem com erro
+
+	mu       sync.Mutex
+	lockedAt time.Time
+	method   string
+	session  string
+}
+
+func (f *fakeLogind) lock(method, session string) *dbus.Error {
+	if f.fail {
+		return dbus.MakeFailedError(os.ErrPermission)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.lockedAt, f.method, f.session = time.Now(), method, session
+	return nil
+}
+
+func (f *fakeLogind) locked() (time.Time, string, string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.lockedAt, f.method, f.session
+}
+
+type fakeLogindManager struct{ *fakeLogind }
+
+func (m fakeLogindManager) GetSessionByPID(pid uint32) (dbus.ObjectPath, *dbus.Error) {
+	return fakeSessionPath, nil
+}
+
+func (m fakeLogindManager) LockSession(id string) *dbus.Error {
+	return m.lock("Manager.LockSession", id)
+}
+
+type fakeLogindSession struct{ *fakeLogind }
+
+func (s fakeLogindSession) Lock() *dbus.Error {
+	return s.lock("Session.Lock", string(fakeSessionPath))
+}
+
+func startFakeLogind(t *testing.T, fail bool) (string, *fakeLogind) {
+	t.Helper()
+	address := startPrivateBus(t)
+	logind := &fakeLogind{fail: fail}
+	conn := exportService(t, address, "org.freedesktop.login1", "/org/freedesktop/login1",
+		"org.freedesktop.login1.Manager", fakeLogindManager{logind})
+	if err := conn.Export(fakeLogindSession{logind}, fakeSessionPath, "org.freedesktop.login1.Session"); err != nil {
+		t.Fatal(err)
+	}
+	return address, logind
+}
+
+func TestLockScreenActionLocksAfterGracePeriod(t *testing.T) {
+	tests := []struct {
+		name        string
+		sessionID   string
+		wantMethod  string
+		wantSession string
+	}{
+		{"sessão pelo PID", "", "Session.Lock", string(fakeSessionPath)},
+		{"XDG_SESSION_ID", "c2", "Manager.LockSession", "c2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("XDG_SESSION_ID", tt.sessionID)
+			address, logind := startFakeLogind(t, false)
+			var results []ActionResult
+			var requiredBreak time.Duration
+			event := Event{
+				Record:       func(r ActionResult) { results = append(results, r) },
+				RequireBreak: func(d time.Duration) { requiredBreak = d },
+			}
+			action := &LockScreenAction{Config: config.LockScreenConfig{
+				GracePeriod:     300 * time.Millisecond,
+				WarningInterval: 100 * time.Millisecond,
+				EnforceMinBreak: true,
+				MinBreak:        5 * time.Minute,
+				BusAddress:      address,
+			}}
+
+			start := time.Now()
+			if err := action.Execute(context.Background(), event); err != nil {
+				t.Fatal(err)
+			}
+			lockedAt, method, session := logind.locked()
+			if lockedAt.IsZero() {
+				t.Fatal("a sessão não foi bloqueada")
+			}
+			if elapsed := lockedAt.Sub(start); elapsed < action.Config.GracePeriod {
+				t.Errorf("bloqueio após %v, antes da contagem de %v", elapsed, action.Config.GracePeriod)
+			}
+			if method != tt.wantMethod || session != tt.wantSession {
+				t.Errorf("bloqueio por %s(%s), esperado %s(%s)", method, session, tt.wantMethod, tt.wantSession)
+			}
+			if len(results) != 1 || results[0].Type != config.ActionLockScreen || results[0].Err != nil {
+				t.Errorf("resultados registrados = %+v", results)
+			}
+			if requiredBreak != 5*time.Minute {
+				t.Errorf("pausa exigida %v, esperado 5m", requiredBreak)
+			}
+		})
+	}
+}
+
+func TestLockScreenActionCanceledDuringCountdown(t *testing.T) {
+	t.Setenv("XDG_SESSION_ID", "")
+	address, logind := startFakeLogind(t, false)
+	action := &LockScreenAction{Config: config.LockScreenConfig{
+		GracePeriod:     time.Second,
+		WarningInterval: 100 * time.Millisecond,
+		BusAddress:      address,
+	}}
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if err := action.Execute(ctx, Event{}); err != nil {
+		t.Fatal(err)
+	}
+	if lockedAt, _, _ := logind.locked(); !lockedAt.IsZero() {
+		t.Error("a sessão foi bloqueada depois do cancelamento")
+	}
+}
+
+func TestLockScreenActionFallbackCommand(t *testing.T) {
+	t.Setenv("XDG_SESSION_ID", "")
+	failing, _ := startFakeLogind(t, true)
+	tests := []struct {
+		name       string
+		busAddress string
+		command    func(marker string) []string
+		wantErr    bool
+	}{
+		{"Session.Lock falha", failing, func(marker string) []string { return []string{"touch", marker} }, false},
+		{"sem logind no barramento", startPrivateBus(t), func(marker string) []string { return []string{"touch", marker} }, false},
+		{"comando alternativo falha", failing, func(string) []string { return []string{"false"} }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			marker := filepath.Join(t.TempDir(), "bloqueado")
+			var results []ActionResult
+			action := &LockScreenAction{Config: config.LockScreenConfig{
+				GracePeriod:     50 * time.Millisecond,
+				BusAddress:      tt.busAddress,
+				FallbackCommand: tt.command(marker),
+			}}
+			err := action.Execute(context.Background(), Event{Record: func(r ActionResult) { results = append(results, r) }})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Execute() = %v, esperado erro: %v", err, tt.wantErr)
+			}
+			if len(results) != 1 || (results[0].Err != nil) != tt.wantErr {
+				t.Errorf("resultados registrados = %+v", results)
+			}
+			if _, statErr := os.Stat(marker); !tt.wantErr && statErr != nil {
+				t.Errorf("o comando alternativo não rodou: %v", statErr)
+			}
+		})
+	}
+}
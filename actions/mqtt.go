@@ -0,0 +1,69 @@
+package actions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/brutalzinn/focus-helper/config"
+	"github.com/brutalzinn/focus-helper/integrations"
+)
+
+type MQTTAction struct {
+	Config config.MQTTActionConfig
+}
+
+func (a *MQTTAction) Execute(ctx context.Context, event Event) error {
+	msg, err := a.message(event)
+	if err != nil {
+		return err
+	}
+	log.Printf("  -> Executando MQTTAction: %s", msg.Topic)
+	return integrations.PublishMQTT(ctx, a.broker(), msg)
+}
+
+func (a *MQTTAction) Describe(event Event) string {
+	msg, err := a.message(event)
+	if err != nil {
+		return err.Error()
+	}
+	return fmt.Sprintf("publica em %s, tópico %s (QoS %d, retain %t)\npayload: %s",
+		a.broker().URL, msg.Topic, msg.QoS, msg.Retain, msg.Payload)
+}
+
+func (a *MQTTAction) broker() integrations.MQTTBroker {
+	cfg := config.AppConfig.MQTT.WithDefaults()
+	broker := integrations.MQTTBroker{
+		URL:      cfg.Broker,
+		ClientID: cfg.ClientID,
+		Username: cfg.Username,
+		Password: cfg.Password,
+	}
+	if a.Config.Broker != "" {
+		broker.URL = a.Config.Broker
+	}
+	return broker
+}
+
+// message renderiza o tópico e o payload com os dados do evento.
+func (a *MQTTAction) message(event Event) (integrations.MQTTMessage, error) {
+	msg := integrations.MQTTMessage{QoS: a.Config.QoS, Retain: a.Config.Retain}
+	data := event.TemplateData()
+	topic, err := renderTemplate("mqtt_topic", a.Config.Topic, data)
+	if err != nil {
+		return msg, fmt.Errorf("erro ao renderizar tópico MQTT: %w", err)
+	}
+	msg.Topic = topic
+	if a.Config.PayloadTemplate == "" {
+		msg.Payload, err = json.Marshal(data)
+	} else {
+		var rendered string
+		rendered, err = renderTemplate("mqtt_payload", a.Config.PayloadTemplate, data)
+		msg.Payload = []byte(rendered)
+	}
+	if err != nil {
+		return msg, fmt.Errorf("erro ao renderizar payload MQTT: %w", err)
+	}
+	return msg, nil
+}
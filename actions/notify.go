@@ -0,0 +1,137 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/brutalzinn/focus-helper/config"
+	"github.com/brutalzinn/focus-helper/notifications"
+)
+
+const defaultSnooze = 10 * time.Minute
+
+var defaultNotifyButtons = []config.NotifyButton{
+	{Label: "Fazendo uma pausa", Action: config.NotifyAcknowledge},
+	{Label: "Adiar 10 min", Action: config.NotifySnooze, Snooze: defaultSnooze},
+}
+
+type NotifyAction struct {
+	Config config.NotifyConfig
+}
+
+// Execute envia a notificação e repassa o botão pressionado ao gerenciador de alertas.
+func (a *NotifyAction) Execute(ctx context.Context, event Event) error {
+	log.Println("  -> Executando NotifyAction")
+	title, body, err := a.render(event)
+	if err != nil {
+		return err
+	}
+	buttons := a.buttons()
+	notifyButtons := make([]notifications.NotificationButton, len(buttons))
+	for i, button := range buttons {
+		notifyButtons[i] = notifications.NotificationButton{Key: fmt.Sprint(i), Label: button.Label}
+	}
+
+	key, err := notifications.Notify(ctx, notifications.Notification{
+		BusAddress: a.Config.BusAddress,
+		AppName:    "Focus Helper",
+		Title:      title,
+		Body:       body,
+		Urgency:    notifyUrgency(a.Config.Urgency, event.Level),
+		Buttons:    notifyButtons,
+		Timeout:    a.Config.Timeout,
+	})
+	if err != nil {
+		if ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+			return nil
+		}
+		return err
+	}
+	for i, button := range buttons {
+		if key == fmt.Sprint(i) {
+			a.handleButton(event, button)
+		}
+	}
+	return nil
+}
+
+func (a *NotifyAction) Describe(event Event) string {
+	title, body, err := a.render(event)
+	if err != nil {
+		return err.Error()
+	}
+	labels := make([]string, 0, len(a.buttons()))
+	for _, button := range a.buttons() {
+		labels = append(labels, fmt.Sprintf("%s (%s)", button.Label, button.Action))
+	}
+	return fmt.Sprintf("título %q, urgência %d\nmensagem %q\nbotões: %s",
+		title, notifyUrgency(a.Config.Urgency, event.Level), body, strings.Join(labels, ", "))
+}
+
+// render aplica os dados do evento ao título e à mensagem.
+func (a *NotifyAction) render(event Event) (title, body string, err error) {
+	data := event.TemplateData()
+	title = a.Config.Title
+	if title == "" {
+		title = "Focus Helper"
+	}
+	if title, err = renderTemplate("notify_title", title, data); err != nil {
+		return "", "", fmt.Errorf("erro ao renderizar título da notificação: %w", err)
+	}
+	body = data.Message
+	if a.Config.Message != "" {
+		if body, err = renderTemplate("notify_message", a.Config.Message, data); err != nil {
+			return "", "", fmt.Errorf("erro ao renderizar mensagem da notificação: %w", err)
+		}
+	}
+	return title, body, nil
+}
+
+func (a *NotifyAction) buttons() []config.NotifyButton {
+	if len(a.Config.Buttons) == 0 {
+		return defaultNotifyButtons
+	}
+	return a.Config.Buttons
+}
+
+func (a *NotifyAction) handleButton(event Event, button config.NotifyButton) {
+	log.Printf("  -> Botão da notificação pressionado: %s", button.Label)
+	switch button.Action {
+	case config.NotifyAcknowledge:
+		if event.Acknowledge != nil {
+			event.Acknowledge()
+		}
+	case config.NotifySnooze:
+		d := button.Snooze
+		if d <= 0 {
+			d = defaultSnooze
+		}
+		if event.Snooze != nil {
+			event.Snooze(d)
+		}
+	}
+}
+
+// notifyUrgency usa a urgência configurada ou a deriva da severidade do nível.
+func notifyUrgency(configured string, level config.AlertLevel) byte {
+	switch strings.ToLower(configured) {
+	case "low":
+		return notifications.UrgencyLow
+	case "normal":
+		return notifications.UrgencyNormal
+	case "critical":
+		return notifications.UrgencyCritical
+	}
+	switch config.LevelSeverity(level) {
+	case config.SeverityCritical:
+		return notifications.UrgencyCritical
+	case config.SeverityLow:
+		return notifications.UrgencyLow
+	default:
+		return notifications.UrgencyNormal
+	}
+}
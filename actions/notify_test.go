@@ -0,0 +1,247 @@
+package actions
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brutalzinn/focus-helper/config"
+	"github.com/godbus/dbus/v5"
+)
+
+// privateBusConfig é um barramento de sessão mínimo, sem serviços ativáveis e
+// sem restrições de política.
+const privateBusConfig = `<!DOCTYPE busconfig PUBLIC "-//freedesktop//DTD D-Bus Bus Configuration 1.0//EN"
+ "http://www.freedesktop.org/standards/dbus/1.0/busconfig.dtd">
+<busconfig>
+  <type>session</type>
+  <listen>unix:dir=%DIR%</listen>
+  <auth>EXTERNAL</auth>
+  <policy context="default">
+    <allow send_destination="*" eavesdrop="true"/>
+    <allow eavesdrop="true"/>
+    <allow own="*"/>
+  </policy>
+</busconfig>
+`
+
+// startPrivateBus sobe um dbus-daemon só para o teste e retorna o endereço.
+func startPrivateBus(t *testing.T) string {
+	t.Helper()
+	daemon, err := exec.LookPath("dbus-daemon")
+	if err != nil {
+		t.Skip("dbus-daemon não encontrado")
+	}
+	// O caminho do socket tem limite de tamanho; t.TempDir() usa o nome do teste.
+	dir, err := os.MkdirTemp("", "dbus")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	configFile := filepath.Join(dir, "bus.conf")
+	if err := os.WriteFile(configFile, []byte(strings.ReplaceAll(privateBusConfig, "%DIR%", dir)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command(daemon, "--config-file="+configFile, "--nofork", "--print-address=1")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+	address, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil {
+		t.Fatal("dbus-daemon não informou o endereço: ", err)
+	}
+	return strings.TrimSpace(address)
+}
+
+// exportService conecta ao barramento, assume o nome e exporta obj.
+func exportService(t *testing.T, address, name string, path dbus.ObjectPath, iface string, obj any) *dbus.Conn {
+	t.Helper()
+	conn, err := dbus.Connect(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	if err := conn.Export(obj, path, iface); err != nil {
+		t.Fatal(err)
+	}
+	reply, err := conn.RequestName(name, dbus.NameFlagDoNotQueue)
+	if err != nil || reply != dbus.RequestNameReplyPrimaryOwner {
+		t.Fatalf("RequestName(%s) = %v, %v", name, reply, err)
+	}
+	return conn
+}
+
+// notifyCall é uma chamada recebida pelo servidor de notificações simulado.
+type notifyCall struct {
+	AppName string
+	Summary string
+	Body    string
+	Actions []string
+	Urgency byte
+	Timeout int32
+}
+
+type fakeNotifications struct {
+	calls  chan notifyCall
+	closed chan uint32
+}
+
+func (f *fakeNotifications) Notify(appName string, replacesID uint32, icon, summary, body string, actions []string, hints map[string]dbus.Variant, timeout int32) (uint32, *dbus.Error) {
+	urgency, _ := hints["urgency"].Value().(byte)
+	f.calls <- notifyCall{AppName: appName, Summary: summary, Body: body, Actions: actions, Urgency: urgency, Timeout: timeout}
+	return 7, nil
+}
+
+func (f *fakeNotifications) CloseNotification(id uint32) *dbus.Error {
+	f.closed <- id
+	return nil
+}
+
+func TestNotifyActionPrivateBus(t *testing.T) {
+	const (
+		service = "org.freedesktop.Notifications"
+		path    = dbus.ObjectPath("/org/freedesktop/Notifications")
+	)
+	address := startPrivateBus(t)
+	fake := &fakeNotifications{calls: make(chan notifyCall, 1), closed: make(chan uint32, 1)}
+	server := exportService(t, address, service, path, service, fake)
+
+	level := config.AlertLevel{Level: "HYPERFOCUS_CRITICAL", Actions: []config.ActionConfig{
+		{Type: config.ActionPopup, PopupMessage: "Faça uma pausa"},
+	}}
+	tests := []struct {
+		name        string
+		config      config.NotifyConfig
+		reply       func(id uint32) // resposta do servidor depois do Notify
+		wantCall    notifyCall
+		wantAck     bool
+		wantSnooze  time.Duration
+		wantClosed  bool
+		cancelAfter time.Duration
+	}{
+		{
+			name:   "botões padrão, acknowledge",
+			config: config.NotifyConfig{},
+			reply: func(id uint32) {
+				server.Emit(path, service+".ActionInvoked", id, "0")
+			},
+			wantCall: notifyCall{
+				AppName: "Focus Helper", Summary: "Focus Helper", Body: "Faça uma pausa",
+				Actions: []string{"0", "Fazendo uma pausa", "1", "Adiar 10 min"},
+				Urgency: 2, Timeout: -1,
+			},
+			wantAck: true,
+		},
+		{
+			name: "templates e soneca configurada",
+			config: config.NotifyConfig{
+				Title:   "Torre: {{.Level}}",
+				Message: "{{.Task}} há {{.SessionMinutes}} min",
+				Urgency: "low",
+				Timeout: 30 * time.Second,
+				Buttons: []config.NotifyButton{
+					{Label: "Ciente", Action: config.NotifyAcknowledge},
+					{Label: "Mais 5", Action: config.NotifySnooze, Snooze: 5 * time.Minute},
+				},
+			},
+			reply: func(id uint32) {
+				server.Emit(path, service+".ActionInvoked", id, "1")
+			},
+			wantCall: notifyCall{
+				AppName: "Focus Helper", Summary: "Torre: HYPERFOCUS_CRITICAL", Body: "relatório há 95 min",
+				Actions: []string{"0", "Ciente", "1", "Mais 5"},
+				Urgency: 0, Timeout: 30000,
+			},
+			wantSnooze: 5 * time.Minute,
+		},
+		{
+			name:   "sinal de outra notificação e fechamento",
+			config: config.NotifyConfig{Urgency: "normal"},
+			reply: func(id uint32) {
+				server.Emit(path, service+".ActionInvoked", id+1, "0")
+				server.Emit(path, service+".NotificationClosed", id, uint32(2))
+			},
+			wantCall: notifyCall{
+				AppName: "Focus Helper", Summary: "Focus Helper", Body: "Faça uma pausa",
+				Actions: []string{"0", "Fazendo uma pausa", "1", "Adiar 10 min"},
+				Urgency: 1, Timeout: -1,
+			},
+		},
+		{
+			name:   "alerta cancelado fecha a notificação",
+			config: config.NotifyConfig{},
+			reply:  func(uint32) {},
+			wantCall: notifyCall{
+				AppName: "Focus Helper", Summary: "Focus Helper", Body: "Faça uma pausa",
+				Actions: []string{"0", "Fazendo uma pausa", "1", "Adiar 10 min"},
+				Urgency: 2, Timeout: -1,
+			},
+			wantClosed:  true,
+			cancelAfter: 200 * time.Millisecond,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			acked := false
+			var snoozed time.Duration
+			event := Event{
+				Level:           level,
+				State:           &config.HyperfocusState{Task: "relatório"},
+				SessionDuration: 95 * time.Minute,
+				Acknowledge:     func() { acked = true },
+				Snooze:          func(d time.Duration) { snoozed = d },
+			}
+			cfg := tt.config
+			cfg.BusAddress = address
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if tt.cancelAfter > 0 {
+				time.AfterFunc(tt.cancelAfter, cancel)
+			}
+
+			done := make(chan error, 1)
+			go func() { done <- (&NotifyAction{Config: cfg}).Execute(ctx, event) }()
+			select {
+			case call := <-fake.calls:
+				if call.AppName != tt.wantCall.AppName || call.Summary != tt.wantCall.Summary || call.Body != tt.wantCall.Body ||
+					!slices.Equal(call.Actions, tt.wantCall.Actions) || call.Urgency != tt.wantCall.Urgency || call.Timeout != tt.wantCall.Timeout {
+					t.Errorf("Notify recebeu %+v, esperado %+v", call, tt.wantCall)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("Notify não foi chamado")
+			}
+			tt.reply(7)
+
+			if err := <-done; err != nil {
+				t.Fatal(err)
+			}
+			if acked != tt.wantAck || snoozed != tt.wantSnooze {
+				t.Errorf("acknowledge %v, soneca %v; esperado %v, %v", acked, snoozed, tt.wantAck, tt.wantSnooze)
+			}
+			select {
+			case id := <-fake.closed:
+				if !tt.wantClosed || id != 7 {
+					t.Errorf("CloseNotification(%d) inesperado", id)
+				}
+			case <-time.After(100 * time.Millisecond):
+				if tt.wantClosed {
+					t.Error("a notificação não foi fechada no cancelamento")
+				}
+			}
+		})
+	}
+}
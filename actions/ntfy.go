@@ -0,0 +1,79 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"mime"
+	"strconv"
+	"strings"
+
+	"github.com/brutalzinn/focus-helper/config"
+	"github.com/brutalzinn/focus-helper/integrations"
+)
+
+type NtfyAction struct {
+	Config config.NtfyConfig
+}
+
+// Execute publica a notificação no tópico ntfy pelo protocolo HTTP simples:
+// o corpo é a mensagem e os metadados vão nos cabeçalhos.
+func (a *NtfyAction) Execute(ctx context.Context, event Event) error {
+	log.Printf("  -> Executando NtfyAction: %s", a.Config.Topic)
+	req, err := a.request(event)
+	if err != nil {
+		return err
+	}
+	err = integrations.SendWebhook(ctx, req)
+	if err != nil {
+		event.record(ActionResult{Type: config.ActionNtfy, Err: err})
+	}
+	return err
+}
+
+func (a *NtfyAction) Describe(event Event) string {
+	req, err := a.request(event)
+	if err != nil {
+		return err.Error()
+	}
+	return fmt.Sprintf("POST %s\ncabeçalhos: %v\nmensagem: %s", req.URL, req.Headers, req.Body)
+}
+
+// request monta a publicação com o título e a mensagem renderizados.
+func (a *NtfyAction) request(event Event) (integrations.WebhookRequest, error) {
+	data := event.TemplateData()
+	message := data.Message
+	var err error
+	if a.Config.Message != "" {
+		if message, err = renderTemplate("ntfy_message", a.Config.Message, data); err != nil {
+			return integrations.WebhookRequest{}, fmt.Errorf("erro ao renderizar mensagem ntfy: %w", err)
+		}
+	}
+	headers := make(map[string]string)
+	if a.Config.Title != "" {
+		title, err := renderTemplate("ntfy_title", a.Config.Title, data)
+		if err != nil {
+			return integrations.WebhookRequest{}, fmt.Errorf("erro ao renderizar título ntfy: %w", err)
+		}
+		// Cabeçalhos HTTP não aceitam UTF-8 puro; o ntfy decodifica RFC 2047.
+		headers["Title"] = mime.BEncoding.Encode("utf-8", title)
+	}
+	if a.Config.Priority > 0 {
+		headers["Priority"] = strconv.Itoa(a.Config.Priority)
+	}
+	if len(a.Config.Tags) > 0 {
+		headers["Tags"] = strings.Join(a.Config.Tags, ",")
+	}
+
+	server := a.Config.Server
+	if server == "" {
+		server = "https://ntfy.sh"
+	}
+	return integrations.WebhookRequest{
+		URL:         strings.TrimRight(server, "/") + "/" + a.Config.Topic,
+		Headers:     headers,
+		Body:        []byte(message),
+		BearerToken: a.Config.Token,
+		Retries:     2,
+	}, nil
+}
@@ -0,0 +1,100 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/brutalzinn/focus-helper/activity"
+	"github.com/brutalzinn/focus-helper/config"
+	"github.com/brutalzinn/focus-helper/notifications"
+)
+
+type BreakOverlayAction struct {
+	Config config.BreakOverlayConfig
+}
+
+// Execute abre a janela de pausa em tela cheia. Ela continua aberta quando o
+// usuário fica ocioso e só fecha quando o monitor de atividade confirma uma
+// pausa suficiente, quando o alerta é reconhecido ou quando o usuário digita a
+// frase de confirmação.
+func (a *BreakOverlayAction) Execute(ctx context.Context, event Event) error {
+	log.Println("  -> Executando BreakOverlayAction")
+	overlay := a.resolve(event)
+
+	done := make(chan struct{})
+	defer close(done)
+	escaped, err := notifications.ShowBreakOverlay(overlay, breakEnded(ctx, event, done))
+	if err != nil {
+		event.record(ActionResult{Type: config.ActionBreakOverlay, Err: err})
+		return err
+	}
+	if escaped {
+		log.Println("  -> Janela de pausa encerrada pela frase de confirmação.")
+		event.record(ActionResult{Type: config.ActionBreakOverlay, Output: "encerrada sem pausa pela frase de confirmação"})
+		if event.Acknowledge != nil {
+			event.Acknowledge()
+		}
+		return nil
+	}
+	log.Println("  -> Janela de pausa fechada.")
+	return nil
+}
+
+// resolve aplica os padrões à configuração da janela de pausa.
+func (a *BreakOverlayAction) resolve(event Event) notifications.BreakOverlay {
+	overlay := notifications.BreakOverlay{
+		Title:         a.Config.Title,
+		Message:       a.Config.Message,
+		BreakDuration: a.Config.BreakDuration,
+		ConfirmPhrase: a.Config.ConfirmPhrase,
+	}
+	if overlay.Title == "" {
+		overlay.Title = "Torre de Controle: pausa obrigatória"
+	}
+	if overlay.Message == "" {
+		overlay.Message = event.Message()
+	}
+	if overlay.BreakDuration <= 0 {
+		overlay.BreakDuration = activity.RequiredBreak(event.SessionDuration, config.AppConfig.BreakPolicy)
+	}
+	if overlay.ConfirmPhrase == "" {
+		overlay.ConfirmPhrase = "assumo o controle"
+	}
+	return overlay
+}
+
+func (a *BreakOverlayAction) Describe(event Event) string {
+	overlay := a.resolve(event)
+	return fmt.Sprintf("janela em tela cheia %q por %v\nmensagem %q\nfrase de saída %q",
+		overlay.Title, overlay.BreakDuration.Round(time.Second), overlay.Message, overlay.ConfirmPhrase)
+}
+
+// breakEnded retorna um canal fechado quando a pausa imposta por uma ação deve
+// terminar: pausa confirmada, alerta reconhecido ou encerramento do Focus
+// Helper. Ficar ocioso é justamente o objetivo, então o cancelamento por
+// ociosidade não encerra a pausa.
+func breakEnded(ctx context.Context, event Event, done <-chan struct{}) <-chan struct{} {
+	ended := make(chan struct{})
+	go func() {
+		defer close(ended)
+		select {
+		case <-done:
+			return
+		case <-event.BreakConfirmed:
+			return
+		case <-ctx.Done():
+		}
+		if !errors.Is(context.Cause(ctx), ErrUserIdle) {
+			return
+		}
+		select {
+		case <-done:
+		case <-event.BreakConfirmed:
+		case <-event.Shutdown:
+		}
+	}()
+	return ended
+}
@@ -0,0 +1,40 @@
+package actions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/brutalzinn/focus-helper/config"
+	"github.com/brutalzinn/focus-helper/plugins"
+)
+
+// PluginAction executa um tipo de ação registrado por um plugin externo.
+type PluginAction struct {
+	Plugin *plugins.Plugin
+	Type   config.ActionType
+	Config map[string]any
+}
+
+func (a *PluginAction) Execute(ctx context.Context, event Event) error {
+	log.Printf("  -> Executando ação %s do plugin %s", a.Type, a.Plugin.Name)
+	result, err := a.Plugin.Execute(ctx, plugins.ExecuteParams{
+		ActionType: string(a.Type),
+		Event:      event.TemplateData(),
+		Config:     a.Config,
+	})
+	event.record(ActionResult{Type: a.Type, ExitCode: result.ExitCode, Output: result.Output, Err: err})
+	if err != nil {
+		return err
+	}
+	if result.Acknowledge && event.Acknowledge != nil {
+		event.Acknowledge()
+	}
+	return nil
+}
+
+func (a *PluginAction) Describe(event Event) string {
+	cfg, _ := json.Marshal(a.Config)
+	return fmt.Sprintf("plugin %s (%s)\nconfiguração: %s", a.Plugin.Name, a.Plugin.Path, cfg)
+}
@@ -1,19 +1,36 @@
 package actions
 
 import (
+	"context"
+	"fmt"
 	"log"
 
-	"github.com/brutalzinn/focus-helper/config"
 	"github.com/brutalzinn/focus-helper/notifications"
 )
 
 type PopupAction struct {
-	Title   string
-	Message string
+	Title       string
+	Message     string
+	Acknowledge bool // pergunta se o usuário vai pausar e reconhece o alerta na confirmação
 }
 
-func (a *PopupAction) Execute(alert config.AlertLevel) error {
+func (a *PopupAction) Execute(ctx context.Context, event Event) error {
 	log.Printf("  -> Executando PopupAction: %s", a.Title)
-	notifications.ShowPopup(a.Title, a.Message)
+	if !a.Acknowledge || event.Acknowledge == nil {
+		notifications.ShowPopup(a.Title, a.Message)
+		return nil
+	}
+	// Só o botão de confirmação reconhece o alerta; fechar o diálogo não.
+	if notifications.ShowAcknowledgePopup(a.Title, a.Message) && ctx.Err() == nil {
+		event.Acknowledge()
+	}
 	return nil
 }
+
+func (a *PopupAction) Describe(event Event) string {
+	desc := fmt.Sprintf("título %q\nmensagem %q", a.Title, a.Message)
+	if a.Acknowledge {
+		desc += "\nconfirmação \"Sim\" reconhece o alerta"
+	}
+	return desc
+}
@@ -0,0 +1,19 @@
+package actions
+
+import (
+	"testing"
+
+	"github.com/brutalzinn/focus-helper/config"
+)
+
+func TestPopupAcknowledgeIsOptIn(t *testing.T) {
+	for _, optIn := range []bool{false, true} {
+		action, err := NewActionFromConfig(config.AlertLevel{Level: "HIGH"}, config.ActionConfig{Type: config.ActionPopup, PopupTitle: "Pausa", PopupAcknowledge: optIn})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := action.(*PopupAction).Acknowledge; got != optIn {
+			t.Errorf("PopupAcknowledge %v: Acknowledge = %v", optIn, got)
+		}
+	}
+}
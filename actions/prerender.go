@@ -0,0 +1,119 @@
+package actions
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/brutalzinn/focus-helper/audio"
+	"github.com/brutalzinn/focus-helper/config"
+	"github.com/brutalzinn/focus-helper/integrations"
+)
+
+// prerendered guarda, por prompt completo, um texto já gerado pelo LLM e já
+// renderizado no cache de áudio. Cada texto é usado uma vez e substituído em
+// background, para que o próximo alerta também toque sem esperar e sem repetir
+// a mesma frase. inflight limita a uma geração em andamento por prompt.
+var prerendered = struct {
+	sync.Mutex
+	ctx      context.Context
+	texts    map[string]string
+	inflight map[string]bool
+}{texts: make(map[string]string), inflight: make(map[string]bool)}
+
+// PreRender renderiza no cache as mensagens de fallback dos níveis informados.
+// Com RenderCache.PreRender, também gera pelo LLM uma transmissão para cada
+// prompt ATC e a renova depois de cada uso. Prompts que dependem da tarefa do
+// plano de voo não são conhecidos de antemão e continuam gerados na hora.
+func PreRender(ctx context.Context, levels []config.AlertLevel) {
+	prompts := config.AppConfig.RenderCache.PreRender
+	if prompts {
+		prerendered.Lock()
+		prerendered.ctx = ctx
+		prerendered.Unlock()
+	}
+
+	log.Println("Pré-renderizando transmissões ATC...")
+	seen := make(map[string]bool)
+	for _, level := range levels {
+		for _, step := range level.Sequence() {
+			for _, actionCfg := range step.Actions {
+				if actionCfg.Type != config.ActionATC {
+					continue
+				}
+				action, err := NewActionFromConfig(level, actionCfg)
+				if err != nil {
+					continue
+				}
+				a, ok := action.(*ATCAction)
+				if !ok {
+					continue
+				}
+				if err := audio.PreRender(ctx, atcFallbackMessage, a.VoiceVolume, a.BackgroundVolume, a.BackgroundFile); err != nil {
+					log.Printf("Erro ao pré-renderizar mensagem de fallback: %v", err)
+				}
+				prompt := a.prompt(Event{Level: level})
+				if !prompts || seen[prompt] {
+					continue
+				}
+				seen[prompt] = true
+				a.prerender(ctx, prompt)
+				if ctx.Err() != nil {
+					return
+				}
+			}
+		}
+	}
+	log.Println("Pré-renderização ATC concluída.")
+}
+
+// prerender gera o texto do prompt, renderiza a transmissão e a deixa pronta
+// para o próximo alerta que usar o mesmo prompt. Não faz nada se o prompt já
+// tem uma geração em andamento.
+func (a *ATCAction) prerender(ctx context.Context, prompt string) {
+	prerendered.Lock()
+	if prerendered.inflight[prompt] {
+		prerendered.Unlock()
+		return
+	}
+	prerendered.inflight[prompt] = true
+	prerendered.Unlock()
+	defer func() {
+		prerendered.Lock()
+		delete(prerendered.inflight, prompt)
+		prerendered.Unlock()
+	}()
+
+	text, err := integrations.GenerateTextWithLlama(ctx, config.AppConfig.Llama.Model, prompt)
+	if err != nil {
+		log.Printf("Erro ao gerar texto ATC para pré-renderização: %v", err)
+		return
+	}
+	if err := audio.PreRender(ctx, text, a.VoiceVolume, a.BackgroundVolume, a.BackgroundFile); err != nil {
+		log.Printf("Erro ao pré-renderizar transmissão ATC: %v", err)
+		return
+	}
+	prerendered.Lock()
+	defer prerendered.Unlock()
+	prerendered.texts[prompt] = text
+}
+
+// prerenderLater prepara em background a transmissão seguinte do prompt.
+func (a *ATCAction) prerenderLater(prompt string) {
+	prerendered.Lock()
+	ctx := prerendered.ctx
+	prerendered.Unlock()
+	if ctx == nil {
+		return
+	}
+	go a.prerender(ctx, prompt)
+}
+
+// takePrerendered retira o texto pronto para o prompt, se houver.
+func takePrerendered(prompt string) (string, bool) {
+	prerendered.Lock()
+	defer prerendered.Unlock()
+	text, ok := prerendered.texts[prompt]
+	delete(prerendered.texts, prompt)
+	return text, ok
+}
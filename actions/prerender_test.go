@@ -0,0 +1,123 @@
+package actions
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/brutalzinn/focus-helper/config"
+)
+
+// fakeOllama responde cada prompt com uma transmissão numerada, depois de
+// release quando definido.
+func fakeOllama(t *testing.T, release <-chan struct{}) *atomic.Int32 {
+	t.Helper()
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := requests.Add(1)
+		if release != nil {
+			<-release
+		}
+		json.NewEncoder(w).Encode(map[string]string{"response": "Alfa-Um, Torre. Transmissão " + strconv.Itoa(int(n))})
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("OLLAMA_ENDPOINT", srv.URL)
+	return &requests
+}
+
+func resetPrerendered(t *testing.T) {
+	t.Helper()
+	reset := func() {
+		prerendered.Lock()
+		prerendered.ctx = nil
+		clear(prerendered.texts)
+		clear(prerendered.inflight)
+		prerendered.Unlock()
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
+var prerenderLevels = []config.AlertLevel{
+	{Level: "LOW", Actions: []config.ActionConfig{{Type: config.ActionATC, LlamaPrompt: "Lembrete de pausa."}}},
+	{Level: "HIGH", Actions: []config.ActionConfig{
+		{Type: config.ActionATC, LlamaPrompt: "Pausa obrigatória."},
+		{Type: config.ActionSound, SoundFile: "alert_level_3.mp3"},
+	}},
+}
+
+func TestPreRenderPromptsOptIn(t *testing.T) {
+	resetPrerendered(t)
+	requests := fakeOllama(t, nil)
+	config.AppConfig.RenderCache = config.RenderCacheConfig{}
+
+	PreRender(context.Background(), prerenderLevels)
+	(&ATCAction{}).prerenderLater("Lembrete de pausa.")
+	time.Sleep(50 * time.Millisecond)
+	if n := requests.Load(); n != 0 {
+		t.Errorf("%d chamadas ao LLM sem PreRender habilitado", n)
+	}
+
+	config.AppConfig.RenderCache.PreRender = true
+	PreRender(context.Background(), prerenderLevels)
+	if n := requests.Load(); n != 2 {
+		t.Errorf("%d chamadas ao LLM, esperado uma por prompt ATC", n)
+	}
+	low := (&ATCAction{LlamaPrompt: "Lembrete de pausa."}).prompt(Event{Level: prerenderLevels[0]})
+	if text, ok := takePrerendered(low); !ok || text == "" {
+		t.Error("transmissão pré-renderizada do LOW não encontrada")
+	}
+	if _, ok := takePrerendered(low); ok {
+		t.Error("a transmissão pré-renderizada foi usada duas vezes")
+	}
+}
+
+func TestPrerenderOneInFlightPerPrompt(t *testing.T) {
+	resetPrerendered(t)
+	release := make(chan struct{})
+	requests := fakeOllama(t, release)
+	config.AppConfig.RenderCache = config.RenderCacheConfig{PreRender: true}
+	prerendered.Lock()
+	prerendered.ctx = context.Background()
+	prerendered.Unlock()
+
+	a := &ATCAction{}
+	var wg sync.WaitGroup
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			a.prerender(context.Background(), "Pausa obrigatória.")
+		}()
+	}
+	a.prerenderLater("Pausa obrigatória.")
+	// Outro prompt não espera pelo primeiro.
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		a.prerender(context.Background(), "Lembrete de pausa.")
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for requests.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if n := requests.Load(); n != 2 {
+		t.Errorf("%d gerações em andamento, esperado uma por prompt", n)
+	}
+	close(release)
+	wg.Wait()
+
+	// Terminada a geração, o prompt pode ser renovado de novo.
+	a.prerender(context.Background(), "Pausa obrigatória.")
+	if n := requests.Load(); n != 3 {
+		t.Errorf("%d chamadas ao LLM, esperado uma nova geração depois da anterior", n)
+	}
+}
@@ -1,10 +1,11 @@
 package actions
 
 import (
+	"context"
+	"fmt"
 	"log"
 
 	"github.com/brutalzinn/focus-helper/audio"
-	"github.com/brutalzinn/focus-helper/config"
 )
 
 type SoundAction struct {
@@ -12,7 +13,11 @@ type SoundAction struct {
 	Multiplier float64
 }
 
-func (a *SoundAction) Execute(alert config.AlertLevel) error {
+func (a *SoundAction) Execute(ctx context.Context, event Event) error {
 	log.Printf("  -> Executando SoundAction: %s", a.FilePath)
-	return audio.PlaySound(a.FilePath, a.Multiplier)
+	return audio.PlaySound(ctx, a.FilePath, a.Multiplier)
+}
+
+func (a *SoundAction) Describe(event Event) string {
+	return fmt.Sprintf("arquivo %s, volume x%.1f", audio.AssetPath(a.FilePath), a.Multiplier)
 }
@@ -0,0 +1,96 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/brutalzinn/focus-helper/activity"
+	"github.com/brutalzinn/focus-helper/config"
+	"github.com/brutalzinn/focus-helper/integrations"
+)
+
+type SuspendAppsAction struct {
+	Match     []*regexp.Regexp
+	Allowlist []*regexp.Regexp
+	Duration  time.Duration
+}
+
+func newSuspendAppsAction(cfg config.SuspendAppsConfig) (*SuspendAppsAction, error) {
+	if len(cfg.Match) == 0 {
+		return nil, fmt.Errorf("ação %s sem processos configurados", config.ActionSuspendApps)
+	}
+	match, err := compilePatterns(cfg.Match)
+	if err != nil {
+		return nil, err
+	}
+	allow, err := compilePatterns(cfg.Allowlist)
+	if err != nil {
+		return nil, err
+	}
+	return &SuspendAppsAction{Match: match, Allowlist: allow, Duration: cfg.Duration}, nil
+}
+
+// Execute suspende os processos durante a pausa e os retoma ao fim dela, quando
+// o alerta é reconhecido ou no encerramento do Focus Helper.
+func (a *SuspendAppsAction) Execute(ctx context.Context, event Event) error {
+	log.Println("  -> Executando SuspendAppsAction")
+	procs, err := integrations.FindProcesses(a.Match, a.Allowlist)
+	if err != nil {
+		event.record(ActionResult{Type: config.ActionSuspendApps, Err: err})
+		return err
+	}
+	if len(procs) == 0 {
+		log.Println("  -> Nenhum processo para suspender.")
+		return nil
+	}
+	stateFile := config.AppConfig.SuspendedAppsFile
+	suspended := integrations.SuspendProcesses(stateFile, procs)
+	defer integrations.ResumeProcesses(stateFile, suspended)
+
+	names := make([]string, 0, len(suspended))
+	for _, proc := range suspended {
+		names = append(names, fmt.Sprintf("%s (%d)", proc.Name, proc.PID))
+	}
+	event.record(ActionResult{Type: config.ActionSuspendApps, Output: "suspensos: " + strings.Join(names, ", ")})
+
+	duration := a.Duration
+	if duration <= 0 {
+		duration = activity.RequiredBreak(event.SessionDuration, config.AppConfig.BreakPolicy)
+	}
+	done := make(chan struct{})
+	defer close(done)
+	select {
+	case <-time.After(duration):
+	case <-breakEnded(ctx, event, done):
+	}
+	return nil
+}
+
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("padrão de processo inválido %q: %w", pattern, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+func (a *SuspendAppsAction) Describe(event Event) string {
+	desc := fmt.Sprintf("suspende processos que casam com %v (exceto %v)", a.Match, a.Allowlist)
+	procs, err := integrations.FindProcesses(a.Match, a.Allowlist)
+	if err != nil {
+		return desc + "\n" + err.Error()
+	}
+	names := make([]string, 0, len(procs))
+	for _, proc := range procs {
+		names = append(names, fmt.Sprintf("%s (%d)", proc.Name, proc.PID))
+	}
+	return desc + "\nprocessos agora: " + strings.Join(names, ", ")
+}
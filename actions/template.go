@@ -0,0 +1,28 @@
+package actions
+
+import (
+	"bytes"
+	"encoding/json"
+	"text/template"
+)
+
+var templateFuncs = template.FuncMap{
+	// json escapa um valor para uso dentro de um corpo JSON.
+	"json": func(v any) (string, error) {
+		out, err := json.Marshal(v)
+		return string(out), err
+	},
+}
+
+// renderTemplate aplica os dados do evento a um template Go.
+func renderTemplate(name, text string, data TemplateData) (string, error) {
+	tmpl, err := template.New(name).Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
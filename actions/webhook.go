@@ -0,0 +1,59 @@
+package actions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/brutalzinn/focus-helper/config"
+	"github.com/brutalzinn/focus-helper/integrations"
+)
+
+type WebhookAction struct {
+	Config config.WebhookConfig
+}
+
+func (a *WebhookAction) Execute(ctx context.Context, event Event) error {
+	log.Printf("  -> Executando WebhookAction: %s", a.Config.URL)
+	body, err := a.renderBody(event)
+	if err != nil {
+		return err
+	}
+	return integrations.SendWebhook(ctx, integrations.WebhookRequest{
+		URL:           a.Config.URL,
+		Method:        a.Config.Method,
+		Headers:       a.Config.Headers,
+		Body:          body,
+		BearerToken:   a.Config.BearerToken,
+		BasicUser:     a.Config.BasicUser,
+		BasicPassword: a.Config.BasicPassword,
+		Retries:       a.Config.Retries,
+		RetryDelay:    a.Config.RetryDelay,
+		Timeout:       a.Config.Timeout,
+	})
+}
+
+func (a *WebhookAction) renderBody(event Event) ([]byte, error) {
+	data := event.TemplateData()
+	if a.Config.BodyTemplate == "" {
+		return json.Marshal(data)
+	}
+	body, err := renderTemplate("webhook", a.Config.BodyTemplate, data)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao renderizar corpo do webhook: %w", err)
+	}
+	return []byte(body), nil
+}
+
+func (a *WebhookAction) Describe(event Event) string {
+	method := a.Config.Method
+	if method == "" {
+		method = "POST"
+	}
+	body, err := a.renderBody(event)
+	if err != nil {
+		return err.Error()
+	}
+	return fmt.Sprintf("%s %s\ncorpo: %s", method, a.Config.URL, body)
+}
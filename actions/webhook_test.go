@@ -0,0 +1,70 @@
+package actions
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/brutalzinn/focus-helper/config"
+)
+
+func TestWebhookActionBody(t *testing.T) {
+	config.AppConfig.Profile = "teste"
+	event := Event{
+		Level: config.AlertLevel{Level: "HIGH", Actions: []config.ActionConfig{
+			{Type: config.ActionPopup, PopupMessage: "Faça uma pausa"},
+		}},
+		State:           &config.HyperfocusState{Task: "relatório"},
+		Repeat:          2,
+		SessionDuration: 95 * time.Minute,
+	}
+	tests := []struct {
+		name     string
+		template string
+		check    func(t *testing.T, body []byte)
+	}{
+		{
+			name: "JSON padrão com TemplateData",
+			check: func(t *testing.T, body []byte) {
+				var data map[string]any
+				if err := json.Unmarshal(body, &data); err != nil {
+					t.Fatalf("corpo não é JSON: %v: %s", err, body)
+				}
+				want := map[string]any{"level": "HIGH", "session_minutes": float64(95), "repeat": float64(2), "profile": "teste", "message": "Faça uma pausa", "task": "relatório"}
+				for key, v := range want {
+					if data[key] != v {
+						t.Errorf("%s = %v, esperado %v", key, data[key], v)
+					}
+				}
+			},
+		},
+		{
+			name:     "template",
+			template: `{"texto":"{{.Level}} após {{.SessionMinutes}} min em {{.Task}}"}`,
+			check: func(t *testing.T, body []byte) {
+				if want := `{"texto":"HIGH após 95 min em relatório"}`; string(body) != want {
+					t.Errorf("corpo = %s, esperado %s", body, want)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body []byte
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ = io.ReadAll(r.Body)
+			}))
+			defer srv.Close()
+
+			action := &WebhookAction{Config: config.WebhookConfig{URL: srv.URL, BodyTemplate: tt.template}}
+			if err := action.Execute(context.Background(), event); err != nil {
+				t.Fatal(err)
+			}
+			tt.check(t, body)
+		})
+	}
+}
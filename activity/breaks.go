@@ -0,0 +1,45 @@
+package activity
+
+import (
+	"time"
+
+	"github.com/brutalzinn/focus-helper/config"
+)
+
+// BreakResult descreve o efeito de uma pausa sobre a sessão de foco.
+type BreakResult struct {
+	Session    time.Duration // uso acumulado antes da pausa
+	Taken      time.Duration // duração da pausa
+	Required   time.Duration // pausa exigida para a sessão
+	Sufficient bool          // a pausa zerou a sessão
+	Remaining  time.Duration // uso acumulado que permanece após o resfriamento
+}
+
+// RequiredBreak calcula a pausa exigida para uma sessão, proporcional ao seu tamanho.
+func RequiredBreak(session time.Duration, policy config.BreakPolicyConfig) time.Duration {
+	required := time.Duration(session.Hours() * float64(policy.BreakPerHour))
+	if required < policy.MinBreak {
+		required = policy.MinBreak
+	}
+	if policy.MaxBreak > 0 && required > policy.MaxBreak {
+		required = policy.MaxBreak
+	}
+	return required
+}
+
+// EvaluateBreak aplica a política de pausas. Uma pausa curta apenas "resfria"
+// a sessão, reduzindo o uso acumulado na proporção da pausa exigida.
+func EvaluateBreak(session, taken time.Duration, policy config.BreakPolicyConfig) BreakResult {
+	result := BreakResult{
+		Session:  session,
+		Taken:    taken,
+		Required: RequiredBreak(session, policy),
+	}
+	if result.Required <= 0 || taken >= result.Required {
+		result.Sufficient = true
+		return result
+	}
+	remainingRatio := 1 - float64(taken)/float64(result.Required)
+	result.Remaining = time.Duration(float64(session) * remainingRatio).Round(time.Second)
+	return result
+}
@@ -0,0 +1,62 @@
+package activity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/brutalzinn/focus-helper/config"
+)
+
+// prodPolicy é a política de pausas da configuração de produção.
+var prodPolicy = config.BreakPolicyConfig{BreakPerHour: 5 * time.Minute, MinBreak: 5 * time.Minute, MaxBreak: 30 * time.Minute}
+
+func TestRequiredBreak(t *testing.T) {
+	tests := []struct {
+		name    string
+		session time.Duration
+		policy  config.BreakPolicyConfig
+		want    time.Duration
+	}{
+		{"LOW, abaixo do mínimo", 45 * time.Minute, prodPolicy, 5 * time.Minute},
+		{"MEDIUM", 90 * time.Minute, prodPolicy, 7*time.Minute + 30*time.Second},
+		{"HIGH", 2*time.Hour + 30*time.Minute, prodPolicy, 12*time.Minute + 30*time.Second},
+		{"CRITICAL", 4 * time.Hour, prodPolicy, 20 * time.Minute},
+		{"acima do teto", 8 * time.Hour, prodPolicy, 30 * time.Minute},
+		{"sem teto", 8 * time.Hour, config.BreakPolicyConfig{BreakPerHour: 5 * time.Minute}, 40 * time.Minute},
+		{"sem política", 4 * time.Hour, config.BreakPolicyConfig{}, 0},
+	}
+	for _, tt := range tests {
+		if got := RequiredBreak(tt.session, tt.policy); got != tt.want {
+			t.Errorf("%s: RequiredBreak(%v) = %v, esperado %v", tt.name, tt.session, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluateBreak(t *testing.T) {
+	tests := []struct {
+		name           string
+		session, taken time.Duration
+		policy         config.BreakPolicyConfig
+		wantSufficient bool
+		wantRemaining  time.Duration
+	}{
+		{"exatamente a pausa exigida", 4 * time.Hour, 20 * time.Minute, prodPolicy, true, 0},
+		{"um segundo a menos", 4 * time.Hour, 20*time.Minute - time.Second, prodPolicy, false, 12 * time.Second},
+		{"metade da pausa", 4 * time.Hour, 10 * time.Minute, prodPolicy, false, 2 * time.Hour},
+		{"sem pausa", 4 * time.Hour, 0, prodPolicy, false, 4 * time.Hour},
+		{"no mínimo", 45 * time.Minute, 5 * time.Minute, prodPolicy, true, 0},
+		{"abaixo do mínimo", 45 * time.Minute, 5*time.Minute - time.Second, prodPolicy, false, 9 * time.Second},
+		{"no teto", 8 * time.Hour, 30 * time.Minute, prodPolicy, true, 0},
+		{"sem política", 4 * time.Hour, 0, config.BreakPolicyConfig{}, true, 0},
+	}
+	for _, tt := range tests {
+		got := EvaluateBreak(tt.session, tt.taken, tt.policy)
+		if got.Sufficient != tt.wantSufficient || got.Remaining != tt.wantRemaining {
+			t.Errorf("%s: EvaluateBreak(%v, %v) = %+v, esperado suficiente %v e restante %v",
+				tt.name, tt.session, tt.taken, got, tt.wantSufficient, tt.wantRemaining)
+		}
+		if got.Session != tt.session || got.Taken != tt.taken || got.Required != RequiredBreak(tt.session, tt.policy) {
+			t.Errorf("%s: resultado %+v não repete a entrada", tt.name, got)
+		}
+	}
+}
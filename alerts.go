@@ -0,0 +1,298 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/brutalzinn/focus-helper/actions"
+	"github.com/brutalzinn/focus-helper/config"
+	"github.com/brutalzinn/focus-helper/database"
+)
+
+var (
+	errAlertAcknowledged = errors.New("alerta reconhecido pelo usuário")
+	errAlertSnoozed      = errors.New("alerta adiado pelo usuário")
+	errAlertSuperseded   = errors.New("alerta substituído por um nível mais alto")
+)
+
+// alertManager mantém o contexto compartilhado pelos alertas em andamento.
+// Cancelar o contexto interrompe as repetições pendentes de todos eles.
+type alertManager struct {
+	mu     sync.Mutex
+	root   context.Context
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+
+	requireBreak   func(time.Duration) // repassado às ações que impõem uma pausa mínima
+	breakConfirmed chan struct{}       // fechado e recriado a cada pausa suficiente
+	snoozedUntil   time.Time           // alertas disparados antes disso são adiados
+	escalating     bool                // há uma escalada aguardando o prazo
+
+	limiter    *interruptionLimiter
+	active     activeAlert
+	activeID   uint64
+	suppressed map[string]bool // níveis suprimidos aguardando nova tentativa, para logar só a primeira
+}
+
+func newAlertManager(root context.Context) *alertManager {
+	m := &alertManager{
+		root:           root,
+		breakConfirmed: make(chan struct{}),
+		limiter:        newInterruptionLimiter(config.AppConfig.RateLimit),
+		suppressed:     make(map[string]bool),
+	}
+	m.ctx, m.cancel = context.WithCancelCause(root)
+	return m
+}
+
+// fire dispara as ações de um nível de alerta em background. session é o uso
+// acumulado que disparou o alerta. Retorna a decisão do limitador: um alerta
+// suprimido não foi entregue e cabe a quem chamou tentar de novo.
+func (m *alertManager) fire(level config.AlertLevel, state *config.HyperfocusState, session time.Duration) limiterDecision {
+	m.mu.Lock()
+	if snoozed := time.Until(m.snoozedUntil); snoozed > 0 {
+		ctx := m.ctx
+		m.mu.Unlock()
+		log.Printf("Alerta %s adiado por %v (soneca).", level.Level, snoozed.Round(time.Second))
+		go m.after(ctx, snoozed, func() { m.fireWhenAdmitted(ctx, level, state, session+snoozed) })
+		// O alerta adiado dispara no fim da soneca.
+		return admitInterruption
+	}
+	severity := config.LevelSeverity(level)
+	decision, reason := m.limiter.admit(level.Level, severity, m.active, time.Now())
+	switch decision {
+	case suppressInterruption:
+		first := !m.suppressed[level.Level]
+		m.suppressed[level.Level] = true
+		m.mu.Unlock()
+		if first {
+			log.Printf("Alerta %s adiado: %s.", level.Level, reason)
+		}
+		return decision
+	case mergeInterruption:
+		delete(m.suppressed, level.Level)
+		m.mu.Unlock()
+		log.Printf("Alerta %s agrupado: %s.", level.Level, reason)
+		return decision
+	case supersedeInterruption:
+		log.Printf("Alerta %s substitui o alerta %s em andamento.", level.Level, m.active.level)
+		m.cancelLocked(errAlertSuperseded)
+	}
+	delete(m.suppressed, level.Level)
+	m.activeID++
+	m.active = activeAlert{id: m.activeID, level: level.Level, severity: severity}
+	activeID := m.activeID
+	ctx := m.ctx
+	breakConfirmed := m.breakConfirmed
+	m.mu.Unlock()
+
+	lastAnswer, err := database.GetLastWellbeingAnswer(db)
+	if err != nil {
+		log.Printf("Erro ao consultar última resposta de bem-estar: %v", err)
+	}
+	alertID := database.LogAlert(db, level.Level, session)
+	event := actions.Event{
+		Level:       level,
+		State:       state,
+		MaxRepeats:  config.AppConfig.RateLimit.RepeatCap(),
+		Acknowledge: m.acknowledge,
+		Snooze: func(d time.Duration) {
+			m.snooze(d, func(ctx context.Context) { m.fireWhenAdmitted(ctx, level, state, session+d) })
+		},
+		LastWellbeingAnswer: lastAnswer,
+		SessionDuration:     session,
+		Record: func(result actions.ActionResult) {
+			recordActionResult(alertID, result)
+		},
+		RequireBreak:   m.requireBreak,
+		BreakConfirmed: breakConfirmed,
+		Shutdown:       m.root.Done(),
+	}
+	go func() {
+		actions.Execute(ctx, event)
+		m.finish(activeID)
+	}()
+	if escalates(level.Level) {
+		m.scheduleEscalation(ctx, state, session)
+	}
+	return decision
+}
+
+// fireWhenAdmitted dispara o alerta e, enquanto o limitador o suprimir, tenta
+// de novo a cada verificação de atividade até ele ser entregue ou ctx terminar.
+// Serve aos disparos que não se repetem sozinhos, como as fases do pomodoro e
+// o fim de uma soneca.
+func (m *alertManager) fireWhenAdmitted(ctx context.Context, level config.AlertLevel, state *config.HyperfocusState, session time.Duration) {
+	if m.fire(level, state, session).delivered() {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(config.AppConfig.ActivityCheckRate)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				log.Printf("Alerta %s descartado: %v.", level.Level, context.Cause(ctx))
+				return
+			case <-ticker.C:
+			}
+			if m.fire(level, state, session).delivered() {
+				return
+			}
+		}
+	}()
+}
+
+// escalates indica se o nível inicia a escalada ao contato de confiança.
+func escalates(level string) bool {
+	if !config.AppConfig.Escalation.Enabled {
+		return false
+	}
+	return slices.Contains(config.AppConfig.Escalation.Levels, level)
+}
+
+// scheduleEscalation avisa o contato de confiança se o alerta não for
+// reconhecido e o usuário não ficar ocioso dentro do prazo. ctx é o contexto
+// do alerta, cancelado pela ociosidade, pelo reconhecimento ou pela soneca.
+func (m *alertManager) scheduleEscalation(ctx context.Context, state *config.HyperfocusState, session time.Duration) {
+	m.mu.Lock()
+	if m.escalating {
+		m.mu.Unlock()
+		return
+	}
+	m.escalating = true
+	m.mu.Unlock()
+
+	cfg := config.AppConfig.Escalation
+	go func() {
+		defer func() {
+			m.mu.Lock()
+			m.escalating = false
+			m.mu.Unlock()
+		}()
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(cfg.After):
+		}
+		last, err := database.GetLastAlertTime(db, cfg.Alert.Level)
+		if err != nil {
+			log.Printf("Erro ao consultar última escalada: %v", err)
+			return
+		}
+		if since := time.Since(last); since < cfg.MinInterval {
+			log.Printf("Escalada suprimida: a última foi há %v.", since.Round(time.Minute))
+			return
+		}
+		log.Printf("Alerta ignorado por %v. Avisando o contato de confiança.", cfg.After)
+		session += cfg.After
+		alertID := database.LogAlert(db, cfg.Alert.Level, session)
+		actions.Execute(m.root, actions.Event{
+			Level:           cfg.Alert,
+			State:           state,
+			SessionDuration: session,
+			Record: func(result actions.ActionResult) {
+				recordActionResult(alertID, result)
+			},
+			Shutdown: m.root.Done(),
+		})
+	}()
+}
+
+// cancelAll interrompe os alertas em andamento; os próximos disparos usam um
+// novo contexto.
+func (m *alertManager) cancelAll(cause error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cancelLocked(cause)
+}
+
+func (m *alertManager) cancelLocked(cause error) {
+	if m.ctx.Err() == nil {
+		log.Printf("Cancelando alertas em andamento: %v", cause)
+	}
+	m.cancel(cause)
+	m.ctx, m.cancel = context.WithCancelCause(m.root)
+	if cause != errAlertSnoozed {
+		m.snoozedUntil = time.Time{}
+	}
+}
+
+// finish marca o fim das ações do alerta, se ele ainda for o ativo.
+func (m *alertManager) finish(id uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.active.id == id {
+		m.active = activeAlert{}
+	}
+}
+
+// admitWellbeingQuestion consulta o limitador antes de uma pergunta de bem-estar.
+func (m *alertManager) admitWellbeingQuestion() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	decision, reason := m.limiter.admit("WELLBEING", config.SeverityLow, m.active, time.Now())
+	if decision != admitInterruption {
+		log.Printf("Pergunta de bem-estar suprimida: %s.", reason)
+		return false
+	}
+	return true
+}
+
+// confirmBreak avisa as ações em andamento que o usuário fez uma pausa suficiente.
+func (m *alertManager) confirmBreak() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	close(m.breakConfirmed)
+	m.breakConfirmed = make(chan struct{})
+}
+
+func (m *alertManager) acknowledge() {
+	m.cancelAll(errAlertAcknowledged)
+}
+
+// snooze encerra os alertas em andamento e adia os próximos por d; refire
+// dispara de novo o alerta adiado com o contexto dos alertas. Ficar ocioso
+// durante a soneca a descarta.
+func (m *alertManager) snooze(d time.Duration, refire func(context.Context)) {
+	log.Printf("Alertas adiados por %v.", d)
+	m.cancelAll(errAlertSnoozed)
+	m.mu.Lock()
+	m.snoozedUntil = time.Now().Add(d)
+	ctx := m.ctx
+	m.mu.Unlock()
+	go m.after(ctx, d, func() { refire(ctx) })
+}
+
+// snoozedUntilTime retorna o fim da soneca atual, ou zero.
+func (m *alertManager) snoozedUntilTime() time.Time {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.snoozedUntil
+}
+
+// after executa fn depois de d, a menos que ctx seja cancelado antes.
+func (m *alertManager) after(ctx context.Context, d time.Duration, fn func()) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(d):
+		fn()
+	}
+}
+
+func recordActionResult(alertID int64, result actions.ActionResult) {
+	record := database.AlertActionRecord{
+		AlertID:    alertID,
+		ActionType: string(result.Type),
+		ExitCode:   result.ExitCode,
+		Output:     result.Output,
+	}
+	if result.Err != nil {
+		record.Error = result.Err.Error()
+	}
+	database.LogAlertAction(db, record)
+}
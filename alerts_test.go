@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/brutalzinn/focus-helper/config"
+)
+
+// newTestAlerts troca o gerenciador global por um com o limite informado.
+func newTestAlerts(t *testing.T, limit config.RateLimitConfig) {
+	t.Helper()
+	config.AppConfig.RateLimit = limit
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	alerts = newAlertManager(ctx)
+}
+
+func TestCheckThresholdsRetriesSuppressedLevel(t *testing.T) {
+	openTestDB(t)
+	newTestAlerts(t, config.RateLimitConfig{Enabled: true, MaxPerHour: 1, MergeWindow: time.Minute})
+	config.AppConfig.AlertLevels = []config.AlertLevel{{Enabled: true, Level: "HIGH", Threshold: time.Hour}}
+	// Uma interrupção recente esgotou o limite da hora.
+	alerts.limiter.history = []time.Time{time.Now().Add(-10 * time.Minute)}
+
+	state := &AppState{continuousUsageStartTime: time.Now().Add(-2 * time.Hour), warnedThresholds: make(map[string]bool)}
+	checkThresholds(state, 2*time.Hour)
+	if state.warnedThresholds["HIGH"] || state.currentHyperfocusState != nil {
+		t.Fatal("nível adiado pelo limitador foi marcado como disparado")
+	}
+
+	// Com o limite liberado, a próxima verificação dispara o nível.
+	alerts.limiter.history = nil
+	checkThresholds(state, 2*time.Hour)
+	if !state.warnedThresholds["HIGH"] || state.currentHyperfocusState == nil || state.currentHyperfocusState.Level != "HIGH" {
+		t.Errorf("nível não disparou depois de liberado: %v %+v", state.warnedThresholds, state.currentHyperfocusState)
+	}
+}
+
+func TestFireMergesIntoActiveAlert(t *testing.T) {
+	openTestDB(t)
+	newTestAlerts(t, config.RateLimitConfig{Enabled: true, MaxPerHour: 6, MergeWindow: time.Minute})
+	alerts.active = activeAlert{id: 7, level: "HIGH", severity: config.SeverityHigh}
+	alerts.activeID = 7
+
+	if got := alerts.fire(config.AlertLevel{Level: "DAILY_HIGH", Severity: config.SeverityHigh}, nil, time.Hour); got != mergeInterruption {
+		t.Fatalf("decisão %v, esperado agrupar ao alerta ativo", got)
+	}
+	if alerts.active.level != "HIGH" || alerts.activeID != 7 {
+		t.Errorf("o alerta agrupado substituiu o ativo: %+v", alerts.active)
+	}
+	if len(alerts.limiter.history) != 0 {
+		t.Error("o alerta agrupado contou como nova interrupção")
+	}
+}
+
+func TestFireWhenAdmittedRetries(t *testing.T) {
+	openTestDB(t)
+	newTestAlerts(t, config.RateLimitConfig{Enabled: true, MaxPerHour: 1})
+	config.AppConfig.ActivityCheckRate = 10 * time.Millisecond
+	alerts.limiter.history = []time.Time{time.Now()}
+
+	level := config.AlertLevel{Level: "POMODORO_BREAK", Severity: config.SeverityMedium}
+	alerts.fireWhenAdmitted(context.Background(), level, nil, 0)
+	time.Sleep(50 * time.Millisecond)
+	alerts.mu.Lock()
+	if alerts.active.level != "" {
+		t.Fatal("alerta disparado acima do limite")
+	}
+	alerts.limiter.history = nil
+	alerts.mu.Unlock()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		alerts.mu.Lock()
+		fired := alerts.activeID > 0
+		alerts.mu.Unlock()
+		if fired {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("o alerta adiado não foi disparado depois de liberado o limite")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
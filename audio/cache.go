@@ -0,0 +1,149 @@
+package audio
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/brutalzinn/focus-helper/config"
+	"github.com/faiface/beep"
+	"github.com/faiface/beep/wav"
+)
+
+// renderCache guarda transmissões renderizadas em disco, um wav por chave. A
+// data de modificação de cada arquivo marca o último uso e orienta a remoção
+// dos menos usados quando o cache passa do tamanho máximo.
+type renderCache struct {
+	dir      string
+	maxBytes int64
+	mu       sync.Mutex
+}
+
+var (
+	cacheOnce   sync.Once
+	sharedCache *renderCache
+)
+
+// radioCache retorna o cache configurado, ou nil se estiver desativado.
+func radioCache() *renderCache {
+	cacheOnce.Do(func() {
+		cfg := config.AppConfig.RenderCache
+		if !cfg.Enabled {
+			return
+		}
+		dir := cfg.Dir
+		if dir == "" {
+			base, err := os.UserCacheDir()
+			if err != nil {
+				base = os.TempDir()
+			}
+			dir = filepath.Join(base, "focus-helper", "radio")
+		}
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			log.Printf("Cache de renderização desativado: %v", err)
+			return
+		}
+		sharedCache = &renderCache{dir: dir, maxBytes: cfg.MaxBytes}
+	})
+	return sharedCache
+}
+
+// key resume tudo o que altera o áudio final: texto, motor e voz, cadeia de
+// efeitos, taxa de amostragem, som de fundo e volumes.
+func (c *renderCache) key(t transmission, provider TTSProvider) string {
+	data, _ := json.Marshal(struct {
+		transmission
+		Provider   config.TTSProviderType
+		Voice      string
+		Effect     string
+		SampleRate int
+	}{t, provider.Name(), provider.Voice(), radioEffectChain, radioSampleRate})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *renderCache) path(t transmission, provider TTSProvider) string {
+	return filepath.Join(c.dir, c.key(t, provider)+".wav")
+}
+
+// get carrega a transmissão do cache e marca o arquivo como usado agora.
+func (c *renderCache) get(t transmission, provider TTSProvider) ([]float64, bool) {
+	path := c.path(t, provider)
+	samples, err := loadMono(path, radioSampleRate)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("Entrada inválida no cache de renderização, removendo: %v", err)
+			_ = os.Remove(path)
+		}
+		return nil, false
+	}
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	return samples, true
+}
+
+// put grava a transmissão num arquivo temporário e o renomeia, para que outro
+// processo nunca leia um wav incompleto, e depois aplica o limite de tamanho.
+func (c *renderCache) put(t transmission, provider TTSProvider, samples []float64) error {
+	f, err := os.CreateTemp(c.dir, ".tmp-*.wav")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	format := beep.Format{SampleRate: radioSampleRate, NumChannels: 1, Precision: 2}
+	if err := wav.Encode(f, monoStreamer(samples), format); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(f.Name(), c.path(t, provider)); err != nil {
+		return err
+	}
+	c.evict()
+	return nil
+}
+
+// evict remove os arquivos usados há mais tempo até o cache caber em maxBytes.
+func (c *renderCache) evict() {
+	if c.maxBytes <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+	var files []os.FileInfo
+	var total int64
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || filepath.Ext(entry.Name()) != ".wav" {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, info)
+		total += info.Size()
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].ModTime().Before(files[j].ModTime()) })
+	for _, info := range files {
+		if total <= c.maxBytes {
+			break
+		}
+		if err := os.Remove(filepath.Join(c.dir, info.Name())); err == nil {
+			total -= info.Size()
+		}
+	}
+}
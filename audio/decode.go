@@ -0,0 +1,94 @@
+package audio
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/faiface/beep"
+	"github.com/faiface/beep/mp3"
+	"github.com/faiface/beep/wav"
+)
+
+// resampleQuality é a qualidade do reamostrador do beep (1 a 64).
+const resampleQuality = 4
+
+// decodeFile abre um arquivo wav ou mp3 pela extensão.
+func decodeFile(path string) (beep.StreamSeekCloser, beep.Format, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, beep.Format{}, err
+	}
+	var (
+		s      beep.StreamSeekCloser
+		format beep.Format
+	)
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".wav":
+		s, format, err = wav.Decode(f)
+	case ".mp3":
+		s, format, err = mp3.Decode(f)
+	default:
+		f.Close()
+		return nil, beep.Format{}, fmt.Errorf("formato de áudio não suportado: %s", ext)
+	}
+	if err != nil {
+		f.Close()
+		return nil, beep.Format{}, fmt.Errorf("erro ao decodificar %s: %w", path, err)
+	}
+	return s, format, nil
+}
+
+// loadMono decodifica o arquivo inteiro em amostras mono na taxa indicada.
+func loadMono(path string, rate int) ([]float64, error) {
+	return loadMonoScaled(path, rate, 1)
+}
+
+// loadMonoScaled é loadMono tocando o arquivo speed vezes mais rápido, o que
+// também sobe o tom na mesma proporção.
+func loadMonoScaled(path string, rate int, speed float64) ([]float64, error) {
+	s, format, err := decodeFile(path)
+	if err != nil {
+		return nil, err
+	}
+	defer s.Close()
+	source := beep.SampleRate(float64(format.SampleRate) * speed)
+	if source == beep.SampleRate(rate) {
+		return readMono(s)
+	}
+	return readMono(beep.Resample(resampleQuality, source, beep.SampleRate(rate), s))
+}
+
+// readMono consome o streamer até o fim, misturando os dois canais.
+func readMono(s beep.Streamer) ([]float64, error) {
+	var out []float64
+	buf := make([][2]float64, 512)
+	for {
+		n, ok := s.Stream(buf)
+		for _, frame := range buf[:n] {
+			out = append(out, (frame[0]+frame[1])/2)
+		}
+		if !ok {
+			break
+		}
+	}
+	return out, s.Err()
+}
+
+// monoStreamer toca amostras mono nos dois canais.
+func monoStreamer(samples []float64) beep.Streamer {
+	pos := 0
+	return beep.StreamerFunc(func(buf [][2]float64) (int, bool) {
+		if pos >= len(samples) {
+			return 0, false
+		}
+		n := min(len(buf), len(samples)-pos)
+		for i := range n {
+			x := samples[pos+i]
+			buf[i] = [2]float64{x, x}
+		}
+		pos += n
+		return n, true
+	})
+}
@@ -0,0 +1,209 @@
+package audio
+
+import (
+	"math"
+	"time"
+)
+
+// As funções deste arquivo operam sobre amostras mono em []float64, no
+// intervalo [-1, 1], sem depender do alto-falante nem de arquivos.
+
+// radioSampleRate é a taxa usada na renderização das transmissões de rádio.
+const radioSampleRate = 22050
+
+// biquad é um filtro IIR de segunda ordem (fórmulas do "Audio EQ Cookbook").
+type biquad struct {
+	b0, b1, b2, a1, a2 float64
+}
+
+// butterworthQ é o Q de um filtro de dois polos sem ressonância, o mesmo
+// padrão dos efeitos highpass e lowpass do sox.
+const butterworthQ = 1 / math.Sqrt2
+
+func highPass(rate int, freq float64) biquad {
+	w, alpha := biquadParams(rate, freq)
+	cos := math.Cos(w)
+	return newBiquad((1+cos)/2, -(1 + cos), (1+cos)/2, 1+alpha, -2*cos, 1-alpha)
+}
+
+func lowPass(rate int, freq float64) biquad {
+	w, alpha := biquadParams(rate, freq)
+	cos := math.Cos(w)
+	return newBiquad((1-cos)/2, 1-cos, (1-cos)/2, 1+alpha, -2*cos, 1-alpha)
+}
+
+func biquadParams(rate int, freq float64) (w, alpha float64) {
+	w = 2 * math.Pi * freq / float64(rate)
+	return w, math.Sin(w) / (2 * butterworthQ)
+}
+
+func newBiquad(b0, b1, b2, a0, a1, a2 float64) biquad {
+	return biquad{b0: b0 / a0, b1: b1 / a0, b2: b2 / a0, a1: a1 / a0, a2: a2 / a0}
+}
+
+// apply filtra as amostras e retorna um novo slice.
+func (f biquad) apply(samples []float64) []float64 {
+	out := make([]float64, len(samples))
+	var x1, x2, y1, y2 float64
+	for i, x := range samples {
+		y := f.b0*x + f.b1*x1 + f.b2*x2 - f.a1*y1 - f.a2*y2
+		x2, x1 = x1, x
+		y2, y1 = y1, y
+		out[i] = y
+	}
+	return out
+}
+
+// compander reproduz o efeito compand do sox: um seguidor de envelope com
+// ataque e decaimento, uma curva de transferência em dB e um atraso que
+// antecipa a variação de ganho.
+type compander struct {
+	Attack       time.Duration
+	Decay        time.Duration
+	Points       [][2]float64 // pares (entrada, saída) em dB, em ordem crescente
+	Gain         float64      // dB somados à saída
+	InitialLevel float64      // nível inicial do envelope em dB
+	Delay        time.Duration
+}
+
+// radioCompander equivale a "compand 0.3,1 6:-70,-60,-20 -5 -90 0.2",
+// sem o joelho suave.
+var radioCompander = compander{
+	Attack:       300 * time.Millisecond,
+	Decay:        time.Second,
+	Points:       [][2]float64{{-70, -60}, {-20, -20}, {0, 0}},
+	Gain:         -5,
+	InitialLevel: -90,
+	Delay:        200 * time.Millisecond,
+}
+
+func (c compander) apply(samples []float64, rate int) []float64 {
+	attack := smoothing(c.Attack, rate)
+	decay := smoothing(c.Decay, rate)
+	envelope := dbToAmplitude(c.InitialLevel)
+
+	gains := make([]float64, len(samples))
+	for i, x := range samples {
+		level := math.Abs(x)
+		if level > envelope {
+			envelope += (level - envelope) * attack
+		} else {
+			envelope += (level - envelope) * decay
+		}
+		in := amplitudeToDB(envelope)
+		gains[i] = dbToAmplitude(c.transfer(in) - in + c.Gain)
+	}
+
+	delay := int(c.Delay.Seconds() * float64(rate))
+	out := make([]float64, len(samples))
+	for i, x := range samples {
+		out[i] = x * gains[min(i+delay, len(gains)-1)]
+	}
+	return out
+}
+
+// transfer interpola a curva do compander. Fora dos pontos configurados o
+// ganho do ponto mais próximo é mantido.
+func (c compander) transfer(in float64) float64 {
+	points := c.Points
+	if len(points) == 0 {
+		return in
+	}
+	if in <= points[0][0] {
+		return in + points[0][1] - points[0][0]
+	}
+	for i := 1; i < len(points); i++ {
+		x0, y0 := points[i-1][0], points[i-1][1]
+		x1, y1 := points[i][0], points[i][1]
+		if in <= x1 {
+			return y0 + (in-x0)*(y1-y0)/(x1-x0)
+		}
+	}
+	last := points[len(points)-1]
+	return in + last[1] - last[0]
+}
+
+// smoothing converte uma constante de tempo no coeficiente por amostra de um
+// filtro de um polo.
+func smoothing(d time.Duration, rate int) float64 {
+	if d <= 0 {
+		return 1
+	}
+	return 1 - math.Exp(-1/(d.Seconds()*float64(rate)))
+}
+
+func dbToAmplitude(db float64) float64 {
+	return math.Pow(10, db/20)
+}
+
+func amplitudeToDB(amplitude float64) float64 {
+	if amplitude <= 0 {
+		return math.Inf(-1)
+	}
+	return 20 * math.Log10(amplitude)
+}
+
+// normalize ajusta o pico das amostras para 0 dBFS, como "gain -n" no sox.
+func normalize(samples []float64) []float64 {
+	peak := 0.0
+	for _, x := range samples {
+		peak = max(peak, math.Abs(x))
+	}
+	out := make([]float64, len(samples))
+	if peak == 0 {
+		return out
+	}
+	for i, x := range samples {
+		out[i] = x / peak
+	}
+	return out
+}
+
+// loop repete as amostras até completar n amostras.
+func loop(samples []float64, n int) []float64 {
+	out := make([]float64, n)
+	if len(samples) == 0 {
+		return out
+	}
+	for i := 0; i < n; i += len(samples) {
+		copy(out[i:], samples)
+	}
+	return out
+}
+
+// track é uma entrada da mixagem com o seu volume linear.
+type track struct {
+	Samples []float64
+	Volume  float64
+}
+
+// mix soma as faixas com os seus volumes, com o comprimento da primeira, e
+// limita o resultado a [-1, 1].
+func mix(tracks ...track) []float64 {
+	if len(tracks) == 0 {
+		return nil
+	}
+	out := make([]float64, len(tracks[0].Samples))
+	for _, t := range tracks {
+		for i := 0; i < len(out) && i < len(t.Samples); i++ {
+			out[i] += t.Samples[i] * t.Volume
+		}
+	}
+	for i, x := range out {
+		out[i] = max(-1, min(1, x))
+	}
+	return out
+}
+
+// radioEffectChain identifica a cadeia de radioEffect na chave do cache de
+// renderização. Altere-a junto com a cadeia para invalidar o cache.
+const radioEffectChain = "highpass 300, lowpass 3000, compand 0.3,1 -70,-60,-20 -5 -90 0.2, gain -n"
+
+// radioEffect aplica a cadeia de efeitos de rádio à voz: passa-faixa de
+// 300 Hz a 3 kHz, compressão e normalização.
+func radioEffect(samples []float64, rate int) []float64 {
+	samples = highPass(rate, 300).apply(samples)
+	samples = lowPass(rate, 3000).apply(samples)
+	samples = radioCompander.apply(samples, rate)
+	return normalize(samples)
+}
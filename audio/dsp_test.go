@@ -0,0 +1,197 @@
+package audio
+
+import (
+	"math"
+	"math/cmplx"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// sine gera um seno de amplitude amp com a duração indicada em segundos.
+func sine(freq, amp, seconds float64) []float64 {
+	samples := make([]float64, int(seconds*radioSampleRate))
+	for i := range samples {
+		samples[i] = amp * math.Sin(2*math.Pi*freq*float64(i)/radioSampleRate)
+	}
+	return samples
+}
+
+// toneLevel mede a amplitude da componente freq na segunda metade das
+// amostras, depois dos transitórios dos filtros e do compander.
+func toneLevel(samples []float64, freq float64) float64 {
+	samples = samples[len(samples)/2:]
+	var sum complex128
+	for i, x := range samples {
+		sum += complex(x, 0) * cmplx.Exp(complex(0, -2*math.Pi*freq*float64(i)/radioSampleRate))
+	}
+	return 2 * cmplx.Abs(sum) / float64(len(samples))
+}
+
+func TestRadioEffectBandPass(t *testing.T) {
+	tests := []struct {
+		freq     float64
+		minRelDB float64
+		maxRelDB float64
+	}{
+		{freq: 100, minRelDB: math.Inf(-1), maxRelDB: -15},
+		{freq: 5000, minRelDB: math.Inf(-1), maxRelDB: -6},
+		{freq: 1500, minRelDB: -1, maxRelDB: 1},
+	}
+	for _, tt := range tests {
+		// A referência de 1 kHz fica no meio da banda; a normalização do
+		// efeito não altera a relação entre as duas componentes.
+		input := mix(track{Samples: sine(tt.freq, 0.4, 2), Volume: 1}, track{Samples: sine(1000, 0.4, 2), Volume: 1})
+		out := radioEffect(input, radioSampleRate)
+		rel := amplitudeToDB(toneLevel(out, tt.freq) / toneLevel(out, 1000))
+		if rel < tt.minRelDB || rel > tt.maxRelDB {
+			t.Errorf("%v Hz: nível relativo a 1 kHz = %.1f dB, esperado entre %.1f e %.1f", tt.freq, rel, tt.minRelDB, tt.maxRelDB)
+		}
+	}
+}
+
+func TestRadioEffectPassesOneKilohertz(t *testing.T) {
+	out := radioEffect(sine(1000, 0.3, 2), radioSampleRate)
+	if level := toneLevel(out, 1000); level < 0.9 || level > 1.01 {
+		t.Errorf("amplitude de 1 kHz depois do efeito = %.3f, esperado perto de 1", level)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []float64
+		want  []float64
+	}{
+		{"pico positivo", []float64{0.1, 0.25, -0.2}, []float64{0.4, 1, -0.8}},
+		{"pico negativo", []float64{0.1, -0.5}, []float64{0.2, -1}},
+		{"acima de 1", []float64{2, -1}, []float64{1, -0.5}},
+		{"silêncio", []float64{0, 0}, []float64{0, 0}},
+		{"vazio", nil, []float64{}},
+	}
+	for _, tt := range tests {
+		got := normalize(tt.input)
+		if !almostEqual(got, tt.want) {
+			t.Errorf("%s: normalize(%v) = %v, esperado %v", tt.name, tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestLoop(t *testing.T) {
+	tests := []struct {
+		input []float64
+		n     int
+		want  []float64
+	}{
+		{[]float64{1, 2, 3}, 7, []float64{1, 2, 3, 1, 2, 3, 1}},
+		{[]float64{1, 2, 3}, 2, []float64{1, 2}},
+		{[]float64{1, 2}, 4, []float64{1, 2, 1, 2}},
+		{nil, 3, []float64{0, 0, 0}},
+		{[]float64{1}, 0, []float64{}},
+	}
+	for _, tt := range tests {
+		if got := loop(tt.input, tt.n); !slices.Equal(got, tt.want) {
+			t.Errorf("loop(%v, %d) = %v, esperado %v", tt.input, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestMix(t *testing.T) {
+	tests := []struct {
+		name   string
+		tracks []track
+		want   []float64
+	}{
+		{"sem faixas", nil, nil},
+		{"volume", []track{{[]float64{0.5, -0.5}, 0.5}}, []float64{0.25, -0.25}},
+		{"soma", []track{{[]float64{0.2, 0.2}, 1}, {[]float64{0.1, -0.1}, 2}}, []float64{0.4, 0}},
+		{"comprimento da primeira", []track{{[]float64{0.1, 0.1, 0.1}, 1}, {[]float64{0.1}, 1}}, []float64{0.2, 0.1, 0.1}},
+		{"segunda mais longa", []track{{[]float64{0.1}, 1}, {[]float64{0.1, 0.5}, 1}}, []float64{0.2}},
+		{"limite", []track{{[]float64{0.8, -0.8}, 1}, {[]float64{0.8, -0.8}, 1}}, []float64{1, -1}},
+	}
+	for _, tt := range tests {
+		if got := mix(tt.tracks...); !almostEqual(got, tt.want) {
+			t.Errorf("%s: mix = %v, esperado %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCompanderTransfer(t *testing.T) {
+	tests := []struct{ in, want float64 }{
+		{-80, -70},
+		{-70, -60},
+		{-45, -40},
+		{-20, -20},
+		{-10, -10},
+		{0, 0},
+		{6, 6},
+	}
+	for _, tt := range tests {
+		if got := radioCompander.transfer(tt.in); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("transfer(%v) = %v, esperado %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCompanderGain(t *testing.T) {
+	// Com um sinal constante o envelope converge para o nível de entrada, e
+	// o ganho aplicado é transfer(nível) - nível + Gain.
+	tests := []struct{ levelDB, wantGainDB float64 }{
+		{-60, 3},  // -60 -> -52: +8 dB, -5 dB de ganho
+		{-40, -1}, // -40 -> -36: +4 dB
+		{-20, -5}, // acima do joelho: só o ganho
+		{-6, -5},
+	}
+	for _, tt := range tests {
+		amplitude := dbToAmplitude(tt.levelDB)
+		input := make([]float64, 10*radioSampleRate)
+		for i := range input {
+			input[i] = amplitude
+		}
+		out := radioCompander.apply(input, radioSampleRate)
+		got := amplitudeToDB(out[len(out)-1] / amplitude)
+		if math.Abs(got-tt.wantGainDB) > 0.1 {
+			t.Errorf("ganho a %v dB = %.2f dB, esperado %v dB", tt.levelDB, got, tt.wantGainDB)
+		}
+	}
+}
+
+func TestMonoStreamerRoundTrip(t *testing.T) {
+	samples := make([]float64, 1300)
+	for i := range samples {
+		samples[i] = float64(i) / 1300
+	}
+	got, err := readMono(monoStreamer(samples))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got, samples) {
+		t.Errorf("readMono(monoStreamer(x)) difere de x: %d amostras, esperado %d", len(got), len(samples))
+	}
+}
+
+func TestDecodeFileInvalid(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"invalido.wav", "invalido.mp3", "audio.ogg"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("não é áudio"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := decodeFile(path); err == nil {
+			t.Errorf("decodeFile(%s) sem erro", name)
+		}
+	}
+}
+
+func almostEqual(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if math.Abs(a[i]-b[i]) > 1e-9 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,253 @@
+package audio
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/brutalzinn/focus-helper/config"
+)
+
+// duckVolume é a fração do volume original aplicada aos streams das outras
+// aplicações enquanto um alerta toca.
+const duckVolume = 0.2
+
+// sinkInput é um stream de reprodução do PulseAudio/PipeWire, como listado por
+// "pactl -f json list sink-inputs".
+type sinkInput struct {
+	Index      int    `json:"index"`
+	ChannelMap string `json:"channel_map"`
+	Volume     map[string]struct {
+		Value int `json:"value"`
+	} `json:"volume"`
+	Properties map[string]string `json:"properties"`
+}
+
+// volumes retorna o volume bruto de cada canal, na ordem do mapa de canais
+// esperada por set-sink-input-volume.
+func (in sinkInput) volumes() []int {
+	var volumes []int
+	for _, channel := range strings.Split(in.ChannelMap, ",") {
+		if v, ok := in.Volume[channel]; ok {
+			volumes = append(volumes, v.Value)
+		}
+	}
+	return volumes
+}
+
+func (in sinkInput) pid() string {
+	return in.Properties["application.process.id"]
+}
+
+// duckedStream registra um stream reduzido. O PID evita restaurar o volume de
+// outro stream que tenha reutilizado o mesmo índice.
+type duckedStream struct {
+	Index    int    `json:"index"`
+	PID      string `json:"pid"`
+	Binary   string `json:"binary"`
+	Original []int  `json:"original"`
+	Ducked   []int  `json:"ducked"`
+}
+
+// duckOtherStreams reduz o volume dos streams das outras aplicações e deixa o
+// stream do Focus Helper em volume cheio, sem mexer no volume do sink. A trava
+// do arquivo de registro fica com este processo até a restauração, então um
+// segundo processo (como o test-alert) espera em vez de registrar volumes já
+// reduzidos como originais. A função retornada restaura os volumes.
+func duckOtherStreams() (func(), error) {
+	stateFile := config.AppConfig.DuckedStreamsFile
+	unlock, err := lockDuckState(stateFile)
+	if err != nil {
+		return nil, err
+	}
+	ducked, err := duckStreams(stateFile)
+	if err != nil {
+		unlock()
+		return nil, err
+	}
+	return func() {
+		defer unlock()
+		restoreStreams(stateFile, ducked)
+	}, nil
+}
+
+// duckStreams reduz os streams das outras aplicações. Cada stream é gravado no
+// arquivo de registro antes de ser reduzido, para que um crash não deixe nada
+// baixo. Quem chama deve segurar a trava do registro.
+func duckStreams(stateFile string) ([]duckedStream, error) {
+	inputs, err := listSinkInputs()
+	if err != nil {
+		return nil, err
+	}
+	self := strconv.Itoa(os.Getpid())
+	var ducked []duckedStream
+	for _, in := range inputs {
+		if in.pid() == self {
+			if err := setSinkInputVolume(in.Index, "100%"); err != nil {
+				log.Printf("Erro ao ajustar o volume do stream do Focus Helper: %v", err)
+			}
+			continue
+		}
+		original := in.volumes()
+		if len(original) == 0 {
+			continue
+		}
+		stream := duckedStream{
+			Index:    in.Index,
+			PID:      in.pid(),
+			Binary:   in.Properties["application.process.binary"],
+			Original: original,
+		}
+		for _, v := range original {
+			stream.Ducked = append(stream.Ducked, int(float64(v)*duckVolume))
+		}
+		if err := writeDucked(stateFile, append(ducked, stream)); err != nil {
+			log.Printf("Erro ao gravar registro de volumes, %s (%d) não será reduzido: %v", stream.Binary, stream.Index, err)
+			continue
+		}
+		if err := setSinkInputVolume(in.Index, rawVolumes(stream.Ducked)...); err != nil {
+			continue
+		}
+		ducked = append(ducked, stream)
+	}
+	if err := writeDucked(stateFile, ducked); err != nil {
+		log.Printf("Erro ao gravar registro de volumes: %v", err)
+	}
+	log.Printf("Volume de %d stream(s) de outras aplicações reduzido.", len(ducked))
+	return ducked, nil
+}
+
+// RestoreDuckedStreams restaura os volumes registrados por uma execução que
+// terminou num crash enquanto um alerta tocava. Se outro processo estiver
+// tocando um alerta, espera ele restaurar os próprios streams.
+func RestoreDuckedStreams() {
+	stateFile := config.AppConfig.DuckedStreamsFile
+	unlock, err := lockDuckState(stateFile)
+	if err != nil {
+		log.Printf("Erro ao travar registro de volumes reduzidos: %v", err)
+		return
+	}
+	defer unlock()
+	streams, err := readDucked(stateFile)
+	if err != nil {
+		log.Printf("Erro ao ler registro de volumes reduzidos: %v", err)
+		return
+	}
+	if len(streams) > 0 {
+		log.Printf("Restaurando o volume de %d stream(s) reduzido(s) numa execução anterior.", len(streams))
+		restoreStreams(stateFile, streams)
+	}
+}
+
+// lockDuckState trava o registro de volumes entre processos com flock. A trava
+// some sozinha se o processo morrer, e o registro deixado fica para o próximo
+// RestoreDuckedStreams.
+func lockDuckState(stateFile string) (func(), error) {
+	f, err := os.OpenFile(stateFile+".lock", os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return func() {
+		_ = syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		f.Close()
+	}, nil
+}
+
+// restoreStreams devolve o volume original aos streams que ainda existem. Um
+// stream cujo volume mudou desde a redução foi ajustado pelo usuário e fica
+// como está.
+func restoreStreams(stateFile string, streams []duckedStream) {
+	inputs, err := listSinkInputs()
+	if err != nil {
+		log.Printf("Erro ao listar streams para restaurar o volume: %v", err)
+		return
+	}
+	current := make(map[int]sinkInput, len(inputs))
+	for _, in := range inputs {
+		current[in.Index] = in
+	}
+	for _, stream := range streams {
+		in, ok := current[stream.Index]
+		if !ok || in.pid() != stream.PID {
+			continue
+		}
+		if !slices.Equal(in.volumes(), stream.Ducked) {
+			log.Printf("Volume de %s (%d) alterado durante o alerta, mantendo.", stream.Binary, stream.Index)
+			continue
+		}
+		if err := setSinkInputVolume(stream.Index, rawVolumes(stream.Original)...); err != nil {
+			log.Printf("Erro ao restaurar o volume de %s (%d): %v", stream.Binary, stream.Index, err)
+		}
+	}
+	if err := writeDucked(stateFile, nil); err != nil {
+		log.Printf("Erro ao limpar registro de volumes reduzidos: %v", err)
+	}
+}
+
+func listSinkInputs() ([]sinkInput, error) {
+	out, err := exec.Command("pactl", "-f", "json", "list", "sink-inputs").Output()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar sink-inputs: %w", err)
+	}
+	var inputs []sinkInput
+	if err := json.Unmarshal(out, &inputs); err != nil {
+		return nil, fmt.Errorf("saída inesperada do pactl: %w", err)
+	}
+	return inputs, nil
+}
+
+func setSinkInputVolume(index int, volumes ...string) error {
+	args := append([]string{"set-sink-input-volume", strconv.Itoa(index)}, volumes...)
+	return runCommand(exec.Command("pactl", args...))
+}
+
+func rawVolumes(volumes []int) []string {
+	args := make([]string, len(volumes))
+	for i, v := range volumes {
+		args[i] = strconv.Itoa(v)
+	}
+	return args
+}
+
+func readDucked(stateFile string) ([]duckedStream, error) {
+	data, err := os.ReadFile(stateFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var streams []duckedStream
+	err = json.Unmarshal(data, &streams)
+	return streams, err
+}
+
+func writeDucked(stateFile string, streams []duckedStream) error {
+	if len(streams) == 0 {
+		err := os.Remove(stateFile)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	data, err := json.Marshal(streams)
+	if err != nil {
+		return err
+	}
+	tmp := stateFile + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, stateFile)
+}
@@ -0,0 +1,235 @@
+package audio
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brutalzinn/focus-helper/config"
+	"github.com/faiface/beep"
+	"github.com/faiface/beep/wav"
+)
+
+// pactlSinkInputs é a saída de "pactl -f json list sink-inputs" (pactl 16)
+// com um stream estéreo, um mono e um 5.1.
+const pactlSinkInputs = `[{"index":42,"driver":"protocol-native.c","owner_module":"10","client":"55","sink":1,"sample_specification":"s16le 2ch 44100Hz","channel_map":"front-left,front-right","format":"pcm, format.sample_format = \"\\\"s16le\\\"\"  format.rate = \"44100\"  format.channels = \"2\"  format.channel_map = \"\\\"front-left,front-right\\\"\"","corked":false,"mute":false,"volume":{"front-left":{"value":52429,"value_percent":"80%","db":"-5.81 dB"},"front-right":{"value":45875,"value_percent":"70%","db":"-9.29 dB"}},"balance":-0.12,"buffer_latency":70000,"sink_latency":0,"resample_method":"n/a","properties":{"media.name":"Playback","application.name":"Firefox","native-protocol.peer":"UNIX socket client","native-protocol.version":"35","application.process.id":"4242","application.process.user":"piloto","application.process.host":"torre","application.process.binary":"firefox","application.language":"pt_BR.UTF-8","window.x11.display":":0","module-stream-restore.id":"sink-input-by-application-name:Firefox"}},{"index":43,"driver":"protocol-native.c","owner_module":"10","client":"56","sink":1,"sample_specification":"s16le 1ch 22050Hz","channel_map":"mono","format":"pcm, format.sample_format = \"\\\"s16le\\\"\"  format.rate = \"22050\"  format.channels = \"1\"  format.channel_map = \"\\\"mono\\\"\"","corked":true,"mute":false,"volume":{"mono":{"value":65536,"value_percent":"100%","db":"0.00 dB"}},"balance":0.00,"buffer_latency":0,"sink_latency":0,"resample_method":"speex-float-1","properties":{"media.name":"radio_static.wav","application.name":"paplay","application.process.id":"4343","application.process.binary":"paplay"}},{"index":44,"driver":"PipeWire","owner_module":"4294967295","client":"57","sink":1,"sample_specification":"float32le 6ch 48000Hz","channel_map":"front-left,front-right,rear-left,rear-right,front-center,lfe","format":"pcm, format.sample_format = \"\\\"float32le\\\"\"  format.rate = \"48000\"  format.channels = \"6\"","corked":false,"mute":false,"volume":{"front-left":{"value":65536,"value_percent":"100%","db":"0.00 dB"},"front-right":{"value":65536,"value_percent":"100%","db":"0.00 dB"},"rear-left":{"value":32768,"value_percent":"50%","db":"-18.06 dB"},"rear-right":{"value":32768,"value_percent":"50%","db":"-18.06 dB"},"front-center":{"value":49152,"value_percent":"75%","db":"-7.50 dB"},"lfe":{"value":16384,"value_percent":"25%","db":"-36.12 dB"}},"balance":0.00,"buffer_latency":0,"sink_latency":0,"resample_method":"PipeWire","properties":{"application.name":"mpv","application.process.id":"4444","application.process.binary":"mpv"}}]`
+
+func TestSinkInputVolumes(t *testing.T) {
+	var inputs []sinkInput
+	if err := json.Unmarshal([]byte(pactlSinkInputs), &inputs); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		index  int
+		pid    string
+		binary string
+		want   []int
+	}{
+		{42, "4242", "firefox", []int{52429, 45875}},
+		{43, "4343", "paplay", []int{65536}},
+		{44, "4444", "mpv", []int{65536, 65536, 32768, 32768, 49152, 16384}},
+	}
+	if len(inputs) != len(tests) {
+		t.Fatalf("%d sink-inputs, esperado %d", len(inputs), len(tests))
+	}
+	for i, tt := range tests {
+		in := inputs[i]
+		if in.Index != tt.index || in.pid() != tt.pid || in.Properties["application.process.binary"] != tt.binary {
+			t.Errorf("sink-input %d: índice %d, pid %q, binário %q", i, in.Index, in.pid(), in.Properties["application.process.binary"])
+		}
+		if got := in.volumes(); !slices.Equal(got, tt.want) {
+			t.Errorf("sink-input %d: volumes %v, esperado %v", tt.index, got, tt.want)
+		}
+	}
+}
+
+func TestSinkInputVolumesFollowChannelMap(t *testing.T) {
+	// A ordem das chaves do objeto JSON não importa; vale a do mapa de canais.
+	var in sinkInput
+	data := `{"index":1,"channel_map":"front-right,front-left","volume":{"front-left":{"value":100},"front-right":{"value":200}}}`
+	if err := json.Unmarshal([]byte(data), &in); err != nil {
+		t.Fatal(err)
+	}
+	if got := in.volumes(); !slices.Equal(got, []int{200, 100}) {
+		t.Errorf("volumes %v, esperado [200 100]", got)
+	}
+}
+
+func TestDuckedStateFile(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "ducked.json")
+
+	streams, err := readDucked(stateFile)
+	if err != nil || streams != nil {
+		t.Fatalf("registro ausente: %v, %v", streams, err)
+	}
+
+	want := []duckedStream{
+		{Index: 42, PID: "4242", Binary: "firefox", Original: []int{52429, 45875}, Ducked: []int{10485, 9175}},
+		{Index: 43, PID: "4343", Binary: "paplay", Original: []int{65536}, Ducked: []int{13107}},
+	}
+	if err := writeDucked(stateFile, want); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(stateFile + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("arquivo temporário não foi renomeado: %v", err)
+	}
+	got, err := readDucked(stateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("%d streams lidos, esperado %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].Index != want[i].Index || got[i].PID != want[i].PID || got[i].Binary != want[i].Binary ||
+			!slices.Equal(got[i].Original, want[i].Original) || !slices.Equal(got[i].Ducked, want[i].Ducked) {
+			t.Errorf("stream %d = %+v, esperado %+v", i, got[i], want[i])
+		}
+	}
+
+	if err := writeDucked(stateFile, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(stateFile); !os.IsNotExist(err) {
+		t.Errorf("registro vazio deveria ser removido: %v", err)
+	}
+	if err := writeDucked(stateFile, nil); err != nil {
+		t.Errorf("remover registro ausente: %v", err)
+	}
+
+	if err := os.WriteFile(stateFile, []byte("{corrompido"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readDucked(stateFile); err == nil {
+		t.Error("registro corrompido deveria falhar")
+	}
+}
+
+func TestLockDuckStateExcludesOtherHolders(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "ducked.json")
+	unlock, err := lockDuckState(stateFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	acquired := make(chan func())
+	go func() {
+		// Cada chamada abre o arquivo de novo, como outro processo faria.
+		second, err := lockDuckState(stateFile)
+		if err != nil {
+			t.Error(err)
+			second = func() {}
+		}
+		acquired <- second
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("a trava foi obtida enquanto outro detentor a segurava")
+	case <-time.After(100 * time.Millisecond):
+	}
+	unlock()
+	select {
+	case second := <-acquired:
+		second()
+	case <-time.After(5 * time.Second):
+		t.Fatal("a trava não foi liberada")
+	}
+}
+
+// TestDuckNullSink roda contra o servidor PulseAudio/PipeWire da sessão, num
+// null-sink criado pelo teste.
+func TestDuckNullSink(t *testing.T) {
+	if _, err := exec.LookPath("pactl"); err != nil {
+		t.Skip("pactl não encontrado")
+	}
+	if _, err := exec.LookPath("paplay"); err != nil {
+		t.Skip("paplay não encontrado")
+	}
+	if err := exec.Command("pactl", "info").Run(); err != nil {
+		t.Skip("sem servidor PulseAudio/PipeWire: ", err)
+	}
+	config.AppConfig.DuckedStreamsFile = filepath.Join(t.TempDir(), "ducked.json")
+
+	sink := "focus_helper_test_" + strconv.Itoa(os.Getpid())
+	out, err := exec.Command("pactl", "load-module", "module-null-sink", "sink_name="+sink).Output()
+	if err != nil {
+		t.Fatal("load-module module-null-sink: ", err)
+	}
+	module := strings.TrimSpace(string(out))
+	t.Cleanup(func() { exec.Command("pactl", "unload-module", module).Run() })
+
+	// Um wav longo o bastante para o stream existir durante todo o teste.
+	path := filepath.Join(t.TempDir(), "tom.wav")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := wav.Encode(f, monoStreamer(sine(440, 0.1, 60)), beep.Format{SampleRate: radioSampleRate, NumChannels: 1, Precision: 2}); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	player := exec.Command("paplay", "--device="+sink, path)
+	if err := player.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { player.Process.Kill(); player.Wait() })
+	pid := strconv.Itoa(player.Process.Pid)
+
+	streamVolumes := func() []int {
+		t.Helper()
+		inputs, err := listSinkInputs()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, in := range inputs {
+			if in.pid() == pid {
+				return in.volumes()
+			}
+		}
+		return nil
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for streamVolumes() == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("o stream do paplay não apareceu")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	original := streamVolumes()
+	ducked := make([]int, len(original))
+	for i, v := range original {
+		ducked[i] = int(float64(v) * duckVolume)
+	}
+
+	restore, err := duckOtherStreams()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := streamVolumes(); !slices.Equal(got, ducked) {
+		t.Errorf("volume reduzido %v, esperado 20%% de %v", got, original)
+	}
+	restore()
+	if got := streamVolumes(); !slices.Equal(got, original) {
+		t.Errorf("volume restaurado %v, esperado %v", got, original)
+	}
+	if _, err := os.Stat(config.AppConfig.DuckedStreamsFile); !os.IsNotExist(err) {
+		t.Errorf("registro deveria ser removido após restaurar: %v", err)
+	}
+
+	// Crash: os volumes ficam reduzidos e o registro fica no disco.
+	if _, err := duckStreams(config.AppConfig.DuckedStreamsFile); err != nil {
+		t.Fatal(err)
+	}
+	if got := streamVolumes(); !slices.Equal(got, ducked) {
+		t.Fatalf("volume reduzido %v, esperado %v", got, ducked)
+	}
+	RestoreDuckedStreams()
+	if got := streamVolumes(); !slices.Equal(got, original) {
+		t.Errorf("volume após recuperar do crash %v, esperado %v", got, original)
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+
+package audio
+
+import (
+	"fmt"
+	"runtime"
+)
+
+func duckOtherStreams() (func(), error) {
+	return nil, fmt.Errorf("redução por stream não suportada em %s", runtime.GOOS)
+}
+
+func RestoreDuckedStreams() {}
@@ -1,25 +1,28 @@
 package audio
 
 import (
-	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"log"
-	"os"
-	"os/exec"
 	"runtime"
 	"sync"
 	"time"
 
+	"github.com/brutalzinn/focus-helper/config"
 	"github.com/faiface/beep"
+	"github.com/faiface/beep/effects"
 	"github.com/faiface/beep/speaker"
 )
 
 var audioMutex sync.Mutex
 var audioInitialized bool
 
+// speakerRate é a taxa do alto-falante; tudo é reamostrado para ela ao tocar.
+const speakerRate = beep.SampleRate(44100)
+
 func InitSpeaker() {
-	sampleRate := beep.SampleRate(44100)
-	err := speaker.Init(sampleRate, sampleRate.N(time.Second/10))
+	err := speaker.Init(speakerRate, speakerRate.N(time.Second/10))
 	if err != nil {
 		log.Printf("AVISO: Não foi possível inicializar o sistema de áudio: %v", err)
 		audioInitialized = false
@@ -32,120 +35,200 @@ func IsReady() bool {
 	return audioInitialized
 }
 
-func PlaySound(filename string, volume float64) error {
+func PlaySound(ctx context.Context, filename string, volume float64) error {
 	audioMutex.Lock()
 	defer audioMutex.Unlock()
 	if !IsReady() {
 		return nil
 	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
 	if volume <= 0 {
 		log.Println("PlayRadioSimulation Volume deve ser maior que zero, usando volume padrão de 1.0")
 		volume = 1.0
 	}
-	staticAudio := getAssetPath("assets", filename)
-	if err := playPrioritySound(staticAudio, volume); err != nil {
+	s, format, err := decodeFile(getAssetPath("assets", filename))
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+	if err := playPrioritySound(ctx, s, format.SampleRate, volume); err != nil {
 		log.Printf("Error playing final audio with ducking: %v", err)
 		return nil
 	}
 	return nil
 }
 
-func PlayRadioSimulation(message string, voiceVolume, backgroundVolume float64, backgroundSound string) error {
-	audioMutex.Lock()
-	if !IsReady() {
-		log.Println("Sistema de áudio não inicializado, pulando simulação de rádio.")
-		return nil
-	}
+// transmission descreve uma transmissão de rádio a renderizar. Os mesmos
+// campos formam a chave do cache de renderização.
+type transmission struct {
+	Message          string
+	VoiceVolume      float64
+	BackgroundVolume float64
+	Background       string
+}
 
+func newTransmission(message string, voiceVolume, backgroundVolume float64, backgroundSound string) transmission {
 	if voiceVolume <= 0 {
 		voiceVolume = 1.0
 	}
+	if backgroundSound == "" {
+		backgroundVolume = 0
+	} else if backgroundVolume <= 0 {
+		backgroundVolume = 0.5
+	}
+	return transmission{
+		Message:          message,
+		VoiceVolume:      voiceVolume,
+		BackgroundVolume: backgroundVolume,
+		Background:       backgroundSound,
+	}
+}
 
-	modelPath := getAssetPath("voices", "pt_BR-cadu-medium.onnx")
-	configPath := getAssetPath("voices", "pt_BR-cadu-medium.onnx.json")
-
-	tempVoiceRaw := getAssetPath("assets", "temp_voice_raw.wav")
-	tempVoiceFiltered := getAssetPath("assets", "temp_voice_filtered.wav")
-	tempBackgroundCropped := getAssetPath("assets", "temp_background_cropped.wav")
-	finalOutput := getAssetPath("assets", "final_radio_output.wav")
-	backgroundAudioPath := getAssetPath("assets", backgroundSound)
-
-	defer func() {
-		audioMutex.Unlock()
-		log.Println("Limpando arquivos de áudio temporários...")
-		_ = os.Remove(tempVoiceRaw)
-		_ = os.Remove(tempVoiceFiltered)
-		_ = os.Remove(tempBackgroundCropped)
-		_ = os.Remove(finalOutput)
-	}()
+// PlayRadioSimulation sintetiza a mensagem, aplica o efeito de rádio e toca o
+// resultado. Transmissões já renderizadas vêm do cache. A renderização
+// acontece fora do mutex de áudio, então pode correr em paralelo com outro som
+// tocando; só a reprodução é serializada.
+func PlayRadioSimulation(ctx context.Context, message string, voiceVolume, backgroundVolume float64, backgroundSound string) error {
+	if !IsReady() {
+		log.Println("Sistema de áudio não inicializado, pulando simulação de rádio.")
+		return nil
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
 
-	piperCmd := exec.Command("piper", "--model", modelPath, "--config", configPath, "--output_file", tempVoiceRaw)
-	piperCmd.Stdin = bytes.NewBufferString(message)
-	if err := runCommand(piperCmd); err != nil {
-		return fmt.Errorf("erro ao executar piper: %w", err)
+	final, err := renderCached(ctx, newTransmission(message, voiceVolume, backgroundVolume, backgroundSound), TTSProviders(config.AppConfig.TTS))
+	if err != nil {
+		return err
 	}
 
-	soxFilterCmd := exec.Command("sox", tempVoiceRaw, "-r", "22050", "-c", "1", tempVoiceFiltered, "highpass", "300", "lowpass", "3000", "compand", "0.3,1", "6:-70,-60,-20", "-5", "-90", "0.2", "gain", "-n")
-	if err := runCommand(soxFilterCmd); err != nil {
-		return fmt.Errorf("erro ao aplicar efeitos de rádio com sox: %w", err)
+	audioMutex.Lock()
+	defer audioMutex.Unlock()
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err := playPrioritySound(ctx, monoStreamer(final), radioSampleRate, 1.0); err != nil {
+		log.Printf("Error playing final audio with ducking: %v", err)
 	}
+	return nil
+}
 
-	if backgroundSound == "" {
-		log.Println("Nenhum som de fundo especificado, tocando apenas a voz ATC.")
-		return playPrioritySound(tempVoiceFiltered, voiceVolume)
+// PreRender renderiza a transmissão e a guarda no cache sem tocá-la, para que
+// o alerta que a usar toque sem esperar pela síntese.
+func PreRender(ctx context.Context, message string, voiceVolume, backgroundVolume float64, backgroundSound string) error {
+	if radioCache() == nil {
+		return nil
 	}
+	_, err := renderCached(ctx, newTransmission(message, voiceVolume, backgroundVolume, backgroundSound), TTSProviders(config.AppConfig.TTS))
+	return err
+}
 
-	if backgroundVolume <= 0 {
-		backgroundVolume = 0.5
+// renderCached percorre os motores de voz na ordem indicada: usa a
+// transmissão do cache se houver, senão a renderiza. Um motor indisponível ou
+// com falha passa a vez ao seguinte.
+func renderCached(ctx context.Context, t transmission, providers []TTSProvider) ([]float64, error) {
+	cache := radioCache()
+	var errs []error
+	for _, provider := range providers {
+		if err := provider.Available(); err != nil {
+			log.Printf("Motor de voz %s indisponível: %v", provider.Name(), err)
+			errs = append(errs, fmt.Errorf("%s: %w", provider.Name(), err))
+			continue
+		}
+		if cache != nil {
+			if samples, ok := cache.get(t, provider); ok {
+				log.Println("Transmissão encontrada no cache de renderização.")
+				return samples, nil
+			}
+		}
+		samples, err := renderRadio(ctx, t, provider)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if err != nil {
+			log.Printf("Erro ao sintetizar com %s, tentando o próximo motor: %v", provider.Name(), err)
+			errs = append(errs, fmt.Errorf("%s: %w", provider.Name(), err))
+			continue
+		}
+		if cache != nil {
+			if err := cache.put(t, provider, samples); err != nil {
+				log.Printf("Erro ao gravar transmissão no cache: %v", err)
+			}
+		}
+		return samples, nil
 	}
+	return nil, fmt.Errorf("nenhum motor de voz conseguiu sintetizar a mensagem: %w", errors.Join(errs...))
+}
 
-	duration, err := getAudioDuration(tempVoiceFiltered)
+// renderRadio gera a transmissão completa em amostras mono a radioSampleRate.
+// Os arquivos intermediários ficam num diretório temporário exclusivo da
+// chamada, removido ao final mesmo em caso de erro ou cancelamento.
+func renderRadio(ctx context.Context, t transmission, provider TTSProvider) ([]float64, error) {
+	dir, cleanup, err := newRenderDir()
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("erro ao criar diretório temporário: %w", err)
 	}
+	defer func() {
+		log.Println("Limpando arquivos de áudio temporários...")
+		cleanup()
+	}()
 
-	ffmpegCmd := exec.Command("ffmpeg", "-y",
-		"-stream_loop", "-1",
-		"-i", backgroundAudioPath,
-		"-t", fmt.Sprintf("%.3f", duration.Seconds()),
-		"-ar", "22050",
-		"-ac", "1",
-		tempBackgroundCropped,
-	)
-	log.Printf("Executando comando FFmpeg para criar fundo com loop: %s", ffmpegCmd.String())
-	if err := runCommand(ffmpegCmd); err != nil {
-		return fmt.Errorf("erro ao cortar áudio estático: %w", err)
+	voice, err := provider.Synthesize(ctx, t.Message, dir)
+	if err != nil {
+		return nil, err
 	}
+	voice = radioEffect(voice, radioSampleRate)
 
-	soxMixCmd := exec.Command("sox",
-		"-m",
-		"-v", fmt.Sprintf("%.2f", voiceVolume), tempVoiceFiltered,
-		"-v", fmt.Sprintf("%.2f", backgroundVolume), tempBackgroundCropped,
-		finalOutput,
-		"trim", "0", fmt.Sprintf("%.3f", duration.Seconds()),
-	)
-	if err := runCommand(soxMixCmd); err != nil {
-		return fmt.Errorf("erro ao mixar áudio com sox: %w", err)
+	if t.Background == "" {
+		log.Println("Nenhum som de fundo especificado, tocando apenas a voz ATC.")
+		return mix(track{Samples: voice, Volume: t.VoiceVolume}), nil
 	}
 
-	if err := playPrioritySound(finalOutput, 1.0); err != nil {
-		log.Printf("Error playing final audio with ducking: %v", err)
+	background, err := loadMono(getAssetPath("assets", t.Background), radioSampleRate)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao carregar o som de fundo: %w", err)
 	}
-	return nil
+	return mix(
+		track{Samples: voice, Volume: t.VoiceVolume},
+		track{Samples: loop(background, len(voice)), Volume: t.BackgroundVolume},
+	), nil
 }
 
-func playPrioritySound(filename string, volume float64) error {
+func playPrioritySound(ctx context.Context, s beep.Streamer, rate beep.SampleRate, volume float64) error {
 	switch runtime.GOOS {
 	case "linux":
-		log.Println("Using Linux 'virtual sink' method for priority audio.")
-		return playSoundIsolatedLinux(filename, volume)
+		log.Println("Using Linux per-stream ducking for priority audio.")
+		return playSoundIsolatedLinux(ctx, s, rate, volume)
 
 	case "darwin", "windows":
 		log.Printf("Using '%s' 'amplify and lower' method for priority audio.", runtime.GOOS)
-		return playSoundAmplified(filename, volume)
+		return playSoundAmplified(ctx, s, rate, volume)
 
 	default:
 		log.Printf("Priority audio not supported on %s. Playing normally.", runtime.GOOS)
-		return playFile(filename, 1.0)
+		return play(ctx, s, rate, 1.0)
+	}
+}
+
+// play toca o streamer no alto-falante com o volume linear indicado e espera o
+// fim da reprodução. Cancelar ctx interrompe o som imediatamente.
+func play(ctx context.Context, s beep.Streamer, rate beep.SampleRate, volume float64) error {
+	if volume <= 0 {
+		volume = 1.0
+	}
+	done := make(chan struct{})
+	s = beep.Resample(resampleQuality, rate, speakerRate, &effects.Gain{Streamer: s, Gain: volume - 1})
+	ctrl := &beep.Ctrl{Streamer: beep.Seq(s, beep.Callback(func() { close(done) }))}
+	speaker.Play(ctrl)
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		speaker.Lock()
+		ctrl.Streamer = nil
+		speaker.Unlock()
+		return ctx.Err()
 	}
 }
@@ -0,0 +1,67 @@
+package audio
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// renderDirPrefix identifica os diretórios temporários criados por renderização.
+const renderDirPrefix = "render-"
+
+// staleRenderAge é a idade a partir da qual um diretório de renderização é
+// considerado abandonado por uma execução que terminou sem limpar.
+const staleRenderAge = time.Hour
+
+// renderRoot retorna o diretório onde ficam os temporários de renderização:
+// o cache do usuário ($XDG_CACHE_HOME no Linux) ou, sem ele, os.TempDir.
+func renderRoot() string {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		base = os.TempDir()
+	}
+	return filepath.Join(base, "focus-helper", "render")
+}
+
+// newRenderDir cria um diretório exclusivo para uma renderização. A função
+// retornada remove o diretório com tudo o que estiver dentro.
+func newRenderDir() (string, func(), error) {
+	root := renderRoot()
+	if err := os.MkdirAll(root, 0o700); err != nil {
+		return "", nil, err
+	}
+	dir, err := os.MkdirTemp(root, renderDirPrefix+"*")
+	if err != nil {
+		return "", nil, err
+	}
+	return dir, func() {
+		if err := os.RemoveAll(dir); err != nil {
+			log.Printf("Erro ao remover diretório temporário %s: %v", dir, err)
+		}
+	}, nil
+}
+
+// CleanStaleRenders remove os diretórios de renderização deixados por
+// execuções que terminaram num crash. Diretórios recentes podem pertencer a
+// outro processo em execução e são mantidos.
+func CleanStaleRenders() {
+	root := renderRoot()
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasPrefix(entry.Name(), renderDirPrefix) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || time.Since(info.ModTime()) < staleRenderAge {
+			continue
+		}
+		dir := filepath.Join(root, entry.Name())
+		log.Printf("Removendo diretório de renderização abandonado: %s", dir)
+		_ = os.RemoveAll(dir)
+	}
+}
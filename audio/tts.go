@@ -0,0 +1,198 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/brutalzinn/focus-helper/config"
+	"github.com/brutalzinn/focus-helper/integrations"
+)
+
+// TTSProvider é um motor de síntese de voz para as transmissões ATC.
+type TTSProvider interface {
+	Name() config.TTSProviderType
+	// Voice identifica a voz, a velocidade e o tom; faz parte da chave do
+	// cache de renderização.
+	Voice() string
+	// Available retorna um erro quando falta o binário, o modelo ou a
+	// configuração do motor.
+	Available() error
+	// Synthesize retorna a fala em amostras mono a radioSampleRate. dir é um
+	// diretório temporário exclusivo da chamada para arquivos intermediários.
+	Synthesize(ctx context.Context, text, dir string) ([]float64, error)
+}
+
+// TTSProviders monta os motores na ordem de preferência configurada. Sem
+// ordem configurada, só o piper é usado.
+func TTSProviders(cfg config.TTSConfig) []TTSProvider {
+	order := cfg.Order
+	if len(order) == 0 {
+		order = []config.TTSProviderType{config.TTSPiper}
+	}
+	var providers []TTSProvider
+	for _, name := range order {
+		switch name {
+		case config.TTSPiper:
+			providers = append(providers, piperTTS{cfg.Piper})
+		case config.TTSEspeakNG:
+			providers = append(providers, espeakTTS{cfg.EspeakNG})
+		case config.TTSGoogle:
+			providers = append(providers, googleTTS{cfg.Google})
+		}
+	}
+	return providers
+}
+
+// rateAndPitch aplica o padrão 1.0 aos multiplicadores não configurados.
+func rateAndPitch(cfg config.TTSVoiceConfig) (rate, pitch float64) {
+	rate, pitch = cfg.Rate, cfg.Pitch
+	if rate <= 0 {
+		rate = 1
+	}
+	if pitch <= 0 {
+		pitch = 1
+	}
+	return rate, pitch
+}
+
+func voiceKey(voice string, cfg config.TTSVoiceConfig) string {
+	rate, pitch := rateAndPitch(cfg)
+	return fmt.Sprintf("%s rate=%.2f pitch=%.2f", voice, rate, pitch)
+}
+
+// piperTTS usa o piper com um modelo onnx de voices/. O piper não ajusta o
+// tom, então a fala é gerada mais lenta e acelerada na reamostragem.
+type piperTTS struct {
+	cfg config.TTSVoiceConfig
+}
+
+func (p piperTTS) Name() config.TTSProviderType { return config.TTSPiper }
+
+func (p piperTTS) model() string {
+	if p.cfg.Voice == "" {
+		return "pt_BR-cadu-medium"
+	}
+	return p.cfg.Voice
+}
+
+func (p piperTTS) Voice() string { return voiceKey(p.model(), p.cfg) }
+
+func (p piperTTS) Available() error {
+	if _, err := exec.LookPath("piper"); err != nil {
+		return err
+	}
+	for _, ext := range []string{".onnx", ".onnx.json"} {
+		if _, err := os.Stat(getAssetPath("voices", p.model()+ext)); err != nil {
+			return fmt.Errorf("modelo do piper não encontrado: %w", err)
+		}
+	}
+	return nil
+}
+
+func (p piperTTS) Synthesize(ctx context.Context, text, dir string) ([]float64, error) {
+	rate, pitch := rateAndPitch(p.cfg)
+	output := filepath.Join(dir, "voice_raw.wav")
+	cmd := exec.CommandContext(ctx, "piper",
+		"--model", getAssetPath("voices", p.model()+".onnx"),
+		"--config", getAssetPath("voices", p.model()+".onnx.json"),
+		"--length_scale", fmt.Sprintf("%.3f", pitch/rate),
+		"--output_file", output,
+	)
+	cmd.Stdin = bytes.NewBufferString(text)
+	if err := runCommand(cmd); err != nil {
+		return nil, fmt.Errorf("erro ao executar piper: %w", err)
+	}
+	return loadMonoScaled(output, radioSampleRate, pitch)
+}
+
+// espeakTTS usa o espeak-ng, que ajusta velocidade e tom nativamente.
+type espeakTTS struct {
+	cfg config.TTSVoiceConfig
+}
+
+func (e espeakTTS) Name() config.TTSProviderType { return config.TTSEspeakNG }
+
+func (e espeakTTS) voice() string {
+	if e.cfg.Voice == "" {
+		return "pt-br"
+	}
+	return e.cfg.Voice
+}
+
+func (e espeakTTS) Voice() string { return voiceKey(e.voice(), e.cfg) }
+
+func (e espeakTTS) Available() error {
+	_, err := exec.LookPath("espeak-ng")
+	return err
+}
+
+func (e espeakTTS) Synthesize(ctx context.Context, text, dir string) ([]float64, error) {
+	rate, pitch := rateAndPitch(e.cfg)
+	output := filepath.Join(dir, "voice_raw.wav")
+	cmd := exec.CommandContext(ctx, "espeak-ng",
+		"-v", e.voice(),
+		"-s", fmt.Sprint(int(175*rate)), // palavras por minuto; 175 é o padrão
+		"-p", fmt.Sprint(min(99, int(50*pitch))), // 0 a 99; 50 é o padrão
+		"-w", output,
+		"--stdin",
+	)
+	cmd.Stdin = bytes.NewBufferString(text)
+	if err := runCommand(cmd); err != nil {
+		return nil, fmt.Errorf("erro ao executar espeak-ng: %w", err)
+	}
+	return loadMono(output, radioSampleRate)
+}
+
+// googleTTS usa o Google Cloud Text-to-Speech.
+type googleTTS struct {
+	cfg config.GoogleTTSConfig
+}
+
+func (g googleTTS) Name() config.TTSProviderType { return config.TTSGoogle }
+
+func (g googleTTS) voice() string {
+	if g.cfg.Voice == "" {
+		return "pt-BR-Wavenet-B"
+	}
+	return g.cfg.Voice
+}
+
+func (g googleTTS) Voice() string { return voiceKey(g.voice(), g.cfg.TTSVoiceConfig) }
+
+// Available não consulta o serviço; falhas de rede ou de credenciais
+// aparecem na síntese e também levam ao próximo motor.
+func (g googleTTS) Available() error { return nil }
+
+func (g googleTTS) Synthesize(ctx context.Context, text, dir string) ([]float64, error) {
+	timeout := g.cfg.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	rate, pitch := rateAndPitch(g.cfg.TTSVoiceConfig)
+	speech, err := integrations.SynthesizeSpeech(ctx, integrations.GoogleSpeech{
+		Endpoint:   g.cfg.Endpoint,
+		Insecure:   g.cfg.Insecure,
+		Text:       text,
+		Voice:      g.voice(),
+		Rate:       rate,
+		Pitch:      12 * math.Log2(pitch), // multiplicador em semitons
+		SampleRate: radioSampleRate,
+	})
+	if err != nil {
+		return nil, err
+	}
+	output := filepath.Join(dir, "voice_raw.wav")
+	if err := os.WriteFile(output, speech, 0o600); err != nil {
+		return nil, err
+	}
+	return loadMono(output, radioSampleRate)
+}
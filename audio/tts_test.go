@@ -0,0 +1,280 @@
+package audio
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net"
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/texttospeech/apiv1/texttospeechpb"
+	"github.com/brutalzinn/focus-helper/config"
+	"github.com/faiface/beep"
+	"github.com/faiface/beep/wav"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeProvider é um motor de voz controlado pelo teste.
+type fakeProvider struct {
+	name         config.TTSProviderType
+	unavailable  error
+	synthErr     error
+	samples      []float64
+	synthesized  *[]config.TTSProviderType
+	cancelOnCall context.CancelFunc
+}
+
+func (f fakeProvider) Name() config.TTSProviderType { return f.name }
+func (f fakeProvider) Voice() string                { return "fake" }
+func (f fakeProvider) Available() error             { return f.unavailable }
+
+func (f fakeProvider) Synthesize(ctx context.Context, text, dir string) ([]float64, error) {
+	*f.synthesized = append(*f.synthesized, f.name)
+	if f.cancelOnCall != nil {
+		f.cancelOnCall()
+		return nil, ctx.Err()
+	}
+	return f.samples, f.synthErr
+}
+
+func TestRenderCachedFallbackOrder(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	errMissing := errors.New("binário ausente")
+	errSynth := errors.New("falha na síntese")
+	voice := []float64{0.5, -0.25, 0.125}
+
+	tests := []struct {
+		name            string
+		providers       func(calls *[]config.TTSProviderType) []TTSProvider
+		wantSynthesized []config.TTSProviderType
+		wantErrs        []error
+	}{
+		{
+			name: "primeiro indisponível passa ao seguinte",
+			providers: func(calls *[]config.TTSProviderType) []TTSProvider {
+				return []TTSProvider{
+					fakeProvider{name: config.TTSPiper, unavailable: errMissing, synthesized: calls},
+					fakeProvider{name: config.TTSEspeakNG, samples: voice, synthesized: calls},
+				}
+			},
+			wantSynthesized: []config.TTSProviderType{config.TTSEspeakNG},
+		},
+		{
+			name: "falha na síntese passa ao seguinte",
+			providers: func(calls *[]config.TTSProviderType) []TTSProvider {
+				return []TTSProvider{
+					fakeProvider{name: config.TTSGoogle, synthErr: errSynth, synthesized: calls},
+					fakeProvider{name: config.TTSPiper, samples: voice, synthesized: calls},
+					fakeProvider{name: config.TTSEspeakNG, samples: voice, synthesized: calls},
+				}
+			},
+			wantSynthesized: []config.TTSProviderType{config.TTSGoogle, config.TTSPiper},
+		},
+		{
+			name: "todos falham",
+			providers: func(calls *[]config.TTSProviderType) []TTSProvider {
+				return []TTSProvider{
+					fakeProvider{name: config.TTSPiper, unavailable: errMissing, synthesized: calls},
+					fakeProvider{name: config.TTSGoogle, synthErr: errSynth, synthesized: calls},
+				}
+			},
+			wantSynthesized: []config.TTSProviderType{config.TTSGoogle},
+			wantErrs:        []error{errMissing, errSynth},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []config.TTSProviderType
+			samples, err := renderCached(context.Background(), newTransmission("teste", 1, 0, ""), tt.providers(&calls))
+			if !slices.Equal(calls, tt.wantSynthesized) {
+				t.Errorf("motores usados %v, esperado %v", calls, tt.wantSynthesized)
+			}
+			if len(tt.wantErrs) == 0 {
+				if err != nil {
+					t.Fatalf("erro inesperado: %v", err)
+				}
+				if len(samples) != len(voice) {
+					t.Errorf("%d amostras, esperado %d", len(samples), len(voice))
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("esperado erro quando todos os motores falham")
+			}
+			for _, want := range tt.wantErrs {
+				if !errors.Is(err, want) {
+					t.Errorf("erro %v não contém %v", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderCachedStopsWhenCanceled(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var calls []config.TTSProviderType
+	providers := []TTSProvider{
+		fakeProvider{name: config.TTSPiper, cancelOnCall: cancel, synthesized: &calls},
+		fakeProvider{name: config.TTSEspeakNG, samples: []float64{0}, synthesized: &calls},
+	}
+	_, err := renderCached(ctx, newTransmission("teste", 1, 0, ""), providers)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("erro = %v, esperado context.Canceled", err)
+	}
+	if !slices.Equal(calls, []config.TTSProviderType{config.TTSPiper}) {
+		t.Errorf("motores usados %v: o cancelamento não deve passar ao seguinte", calls)
+	}
+}
+
+func TestTTSProvidersOrder(t *testing.T) {
+	tests := []struct {
+		order []config.TTSProviderType
+		want  []config.TTSProviderType
+	}{
+		{nil, []config.TTSProviderType{config.TTSPiper}},
+		{[]config.TTSProviderType{config.TTSGoogle, config.TTSEspeakNG}, []config.TTSProviderType{config.TTSGoogle, config.TTSEspeakNG}},
+		{[]config.TTSProviderType{"DESCONHECIDO", config.TTSPiper}, []config.TTSProviderType{config.TTSPiper}},
+	}
+	for _, tt := range tests {
+		var got []config.TTSProviderType
+		for _, p := range TTSProviders(config.TTSConfig{Order: tt.order}) {
+			got = append(got, p.Name())
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("TTSProviders(%v) = %v, esperado %v", tt.order, got, tt.want)
+		}
+	}
+}
+
+// fakeTextToSpeech é um substituto local do serviço do Google.
+type fakeTextToSpeech struct {
+	texttospeechpb.UnimplementedTextToSpeechServer
+	requests chan *texttospeechpb.SynthesizeSpeechRequest
+	audio    []byte
+}
+
+func (f *fakeTextToSpeech) SynthesizeSpeech(ctx context.Context, req *texttospeechpb.SynthesizeSpeechRequest) (*texttospeechpb.SynthesizeSpeechResponse, error) {
+	f.requests <- req
+	if f.audio == nil {
+		return nil, status.Error(codes.PermissionDenied, "sem credenciais")
+	}
+	return &texttospeechpb.SynthesizeSpeechResponse{AudioContent: f.audio}, nil
+}
+
+func startFakeTextToSpeech(t *testing.T, audio []byte) (string, *fakeTextToSpeech) {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake := &fakeTextToSpeech{requests: make(chan *texttospeechpb.SynthesizeSpeechRequest, 1), audio: audio}
+	srv := grpc.NewServer()
+	texttospeechpb.RegisterTextToSpeechServer(srv, fake)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+	return lis.Addr().String(), fake
+}
+
+// wavBytes codifica amostras mono como o LINEAR16 devolvido pelo Google.
+func wavBytes(t *testing.T, samples []float64) []byte {
+	t.Helper()
+	path := t.TempDir() + "/fala.wav"
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := wav.Encode(f, monoStreamer(samples), beep.Format{SampleRate: radioSampleRate, NumChannels: 1, Precision: 2}); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestGoogleTTSStandIn(t *testing.T) {
+	speech := sine(440, 0.5, 0.5)
+	endpoint, fake := startFakeTextToSpeech(t, wavBytes(t, speech))
+	provider := googleTTS{config.GoogleTTSConfig{
+		TTSVoiceConfig: config.TTSVoiceConfig{Voice: "en-US-Standard-C", Rate: 1.25, Pitch: 2},
+		Endpoint:       endpoint,
+		Insecure:       true,
+	}}
+
+	samples, err := provider.Synthesize(context.Background(), "Torre chamando", t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := <-fake.requests
+	if got := req.GetInput().GetText(); got != "Torre chamando" {
+		t.Errorf("texto = %q", got)
+	}
+	if got := req.GetVoice().GetName(); got != "en-US-Standard-C" {
+		t.Errorf("voz = %q", got)
+	}
+	if got := req.GetVoice().GetLanguageCode(); got != "en-US" {
+		t.Errorf("idioma = %q, esperado en-US", got)
+	}
+	audioCfg := req.GetAudioConfig()
+	if audioCfg.GetSpeakingRate() != 1.25 {
+		t.Errorf("velocidade = %v, esperado 1.25", audioCfg.GetSpeakingRate())
+	}
+	if math.Abs(audioCfg.GetPitch()-12) > 1e-9 {
+		t.Errorf("tom = %v semitons, esperado 12 (uma oitava)", audioCfg.GetPitch())
+	}
+	if audioCfg.GetAudioEncoding() != texttospeechpb.AudioEncoding_LINEAR16 || audioCfg.GetSampleRateHertz() != radioSampleRate {
+		t.Errorf("formato = %v a %d Hz", audioCfg.GetAudioEncoding(), audioCfg.GetSampleRateHertz())
+	}
+	if len(samples) != len(speech) {
+		t.Errorf("%d amostras decodificadas, esperado %d", len(samples), len(speech))
+	}
+}
+
+func TestGoogleTTSDefaults(t *testing.T) {
+	endpoint, fake := startFakeTextToSpeech(t, wavBytes(t, []float64{0}))
+	provider := googleTTS{config.GoogleTTSConfig{Endpoint: endpoint, Insecure: true}}
+	if _, err := provider.Synthesize(context.Background(), "olá", t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	req := <-fake.requests
+	if req.GetVoice().GetName() != "pt-BR-Wavenet-B" || req.GetVoice().GetLanguageCode() != "pt-BR" {
+		t.Errorf("voz padrão = %s (%s)", req.GetVoice().GetName(), req.GetVoice().GetLanguageCode())
+	}
+	if req.GetAudioConfig().GetSpeakingRate() != 1 || req.GetAudioConfig().GetPitch() != 0 {
+		t.Errorf("velocidade %v e tom %v, esperado 1 e 0", req.GetAudioConfig().GetSpeakingRate(), req.GetAudioConfig().GetPitch())
+	}
+}
+
+func TestGoogleTTSErrors(t *testing.T) {
+	endpoint, _ := startFakeTextToSpeech(t, nil)
+	provider := googleTTS{config.GoogleTTSConfig{Endpoint: endpoint, Insecure: true}}
+	if _, err := provider.Synthesize(context.Background(), "olá", t.TempDir()); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("erro = %v, esperado PermissionDenied", err)
+	}
+
+	// Um endpoint sem servidor esgota o Timeout em vez das novas tentativas
+	// do cliente, para não atrasar o fallback.
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closed := lis.Addr().String()
+	lis.Close()
+	provider = googleTTS{config.GoogleTTSConfig{Endpoint: closed, Insecure: true, Timeout: 200 * time.Millisecond}}
+	start := time.Now()
+	if _, err := provider.Synthesize(context.Background(), "olá", t.TempDir()); err == nil {
+		t.Fatal("esperado erro com endpoint fechado")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("síntese levou %v apesar do timeout de 200ms", elapsed)
+	}
+}
@@ -2,33 +2,17 @@ package audio
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"log"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
-	"strconv"
 	"strings"
-	"time"
-)
-
-func getSystemVolumeLinux() (string, error) {
-	cmdStr := "pactl list sinks | grep 'Volume:' | head -n1 | cut -d'/' -f2 | tr -d ' %'"
-	cmd := exec.Command("bash", "-c", cmdStr)
-
-	output, err := cmd.Output()
-	if err != nil {
-		return "", fmt.Errorf("failed to get linux volume: %w", err)
-	}
-
-	volume := strings.TrimSpace(string(output))
-	if _, err := strconv.Atoi(volume); err != nil {
-		return "", fmt.Errorf("could not parse volume: %s", volume)
-	}
 
-	return volume + "%", nil
-}
+	"github.com/faiface/beep"
+)
 
 func getSystemVolumeMac() (string, error) {
 	cmd := exec.Command("osascript", "-e", "output volume of (get volume settings)")
@@ -39,48 +23,7 @@ func getSystemVolumeMac() (string, error) {
 	return strings.TrimSpace(string(output)), nil
 }
 
-func playFile(filename string, volume float64) error {
-	playCmd := exec.Command("play", "-q", filename, "vol", fmt.Sprintf("%.2f", volume))
-	return runCommand(playCmd)
-}
-
-func getAudioDuration(filePath string) (time.Duration, error) {
-	// This command asks ffprobe for the duration in seconds.
-	cmd := exec.Command("ffprobe",
-		"-i", filePath,
-		"-show_entries", "format=duration",
-		"-v", "quiet",
-		"-of", "csv=p=0",
-	)
-
-	output, err := cmd.Output()
-	if err != nil {
-		return 0, fmt.Errorf("error running ffprobe on %s: %w", filePath, err)
-	}
-
-	// The output is a string like "12.345600\n", so we trim it.
-	durationStr := strings.TrimSpace(string(output))
-
-	// Parse the string into a float64.
-	durationFloat, err := strconv.ParseFloat(durationStr, 64)
-	if err != nil {
-		return 0, fmt.Errorf("could not parse duration '%s': %w", durationStr, err)
-	}
-
-	// Convert the seconds into a time.Duration.
-	return time.Duration(durationFloat * float64(time.Second)), nil
-}
-
-func playFileOnSink(filename string, multiplier float64, sink string) error {
-	if multiplier <= 0 {
-		multiplier = 1.0
-	}
-	playCmd := exec.Command("play", "-q", filename, "gain", "-n", "vol", fmt.Sprintf("%.2f", multiplier))
-	playCmd.Env = append(os.Environ(), "PULSE_SINK="+sink)
-	return runCommand(playCmd)
-}
-
-func playSoundAmplified(filename string, volume float64) error {
+func playSoundAmplified(ctx context.Context, s beep.Streamer, rate beep.SampleRate, volume float64) error {
 	var lowerVolumeCmd, restoreVolumeCmd *exec.Cmd
 	var originalVolume string
 	var err error
@@ -99,27 +42,13 @@ func playSoundAmplified(filename string, volume float64) error {
 		lowerVolumeCmd = exec.Command("nircmd.exe", "setsysvolume", "13107")   // ~20%
 		restoreVolumeCmd = exec.Command("nircmd.exe", "setsysvolume", "52428") // ~80%
 
-	case "linux":
-		sink, err := getDefaultSinkName()
-		if err != nil {
-			log.Println("Could not get default sink, skipping volume ducking.")
-			return playFile(filename, volume)
-		}
-		originalVolume, err = getSystemVolumeLinux()
-		if err != nil {
-			log.Println("Could not get current volume, defaulting to 100%")
-			originalVolume = "100%"
-		}
-		lowerVolumeCmd = exec.Command("pactl", "set-sink-volume", sink, "20%")
-		restoreVolumeCmd = exec.Command("pactl", "set-sink-volume", sink, originalVolume)
-
 	default:
 		return fmt.Errorf("unsupported OS for this method: %s", runtime.GOOS)
 	}
 
 	if err := runCommand(lowerVolumeCmd); err != nil {
 		log.Println("Could not lower system volume, playing normally.")
-		return playFile(filename, 1.0)
+		return play(ctx, s, rate, 1.0)
 	}
 	defer func() {
 		log.Printf("Restoring system volume to: %s", originalVolume)
@@ -127,28 +56,19 @@ func playSoundAmplified(filename string, volume float64) error {
 	}()
 
 	log.Printf("Playing amplified sound with multiplier %.2f", volume)
-	return playFile(filename, volume)
+	return play(ctx, s, rate, volume)
 }
 
-func playSoundIsolatedLinux(filename string, volume float64) error {
-	originalVolume, err := getSystemVolumeLinux()
+// playSoundIsolatedLinux reduz só os streams das outras aplicações, toca o
+// alerta no próprio stream em volume cheio e restaura cada stream ao final.
+func playSoundIsolatedLinux(ctx context.Context, s beep.Streamer, rate beep.SampleRate, volume float64) error {
+	restore, err := duckOtherStreams()
 	if err != nil {
-		originalVolume = "100%"
-		log.Println("Could not get current volume, defaulting to 100%")
-	}
-
-	if err := runCommand(exec.Command("pactl", "set-sink-volume", "@DEFAULT_SINK@", "20%")); err != nil {
-		log.Println("Failed to lower system volume:", err)
+		log.Printf("Não foi possível reduzir o volume das outras aplicações, tocando normalmente: %v", err)
+		return play(ctx, s, rate, volume)
 	}
-
-	defer func() {
-		time.Sleep(100 * time.Millisecond)
-		if err := runCommand(exec.Command("pactl", "set-sink-volume", "@DEFAULT_SINK@", originalVolume)); err != nil {
-			log.Println("Failed to restore system volume:", err)
-		}
-	}()
-
-	return playSoundAmplified(filename, volume)
+	defer restore()
+	return play(ctx, s, rate, volume)
 }
 
 func runCommand(cmd *exec.Cmd) error {
@@ -174,11 +94,7 @@ func getAssetPath(dir, filename string) string {
 	return absPath
 }
 
-func getDefaultSinkName() (string, error) {
-	cmd := exec.Command("pactl", "get-default-sink")
-	out, err := cmd.Output()
-	if err != nil {
-		return "", err
-	}
-	return strings.TrimSpace(string(out)), nil
+// AssetPath retorna o caminho absoluto de um arquivo de áudio em assets.
+func AssetPath(filename string) string {
+	return getAssetPath("assets", filename)
 }
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	"github.com/brutalzinn/focus-helper/config"
+	"github.com/brutalzinn/focus-helper/database"
+)
+
+// dailyBudget acumula o tempo ativo do dia, persistido no banco para
+// sobreviver a reinícios.
+type dailyBudget struct {
+	day        string
+	active     time.Duration
+	saved      time.Duration // último valor gravado no banco
+	lastSample time.Time     // última amostra ativa; zero depois de uma pausa
+	warned     map[string]bool
+}
+
+func budgetDay(t time.Time) string {
+	return t.Add(-config.AppConfig.DailyBudget.DayBoundary).Format("2006-01-02")
+}
+
+func loadDailyBudget(now time.Time) *dailyBudget {
+	budget := &dailyBudget{day: budgetDay(now), warned: make(map[string]bool)}
+	active, err := database.GetDailyUsage(db, budget.day)
+	if err != nil {
+		log.Printf("Erro ao carregar uso diário: %v", err)
+	}
+	budget.active = active
+	budget.saved = active
+	// Níveis já ultrapassados antes do reinício não devem disparar de novo.
+	for _, level := range config.AppConfig.DailyBudget.AlertLevels {
+		if level.Enabled && active >= level.Threshold {
+			budget.warned[level.Level] = true
+		}
+	}
+	log.Printf("Uso diário carregado para %s: %v", budget.day, active.Round(time.Second))
+	return budget
+}
+
+// track soma o tempo ativo desde a última amostra e dispara os níveis do
+// orçamento diário que foram ultrapassados. O uso é gravado a cada minuto
+// completo e na virada do dia.
+func (b *dailyBudget) track(now time.Time) {
+	if day := budgetDay(now); day != b.day {
+		b.save()
+		log.Printf("Novo dia de uso (%s). Reiniciando orçamento diário.", day)
+		b.day = day
+		b.active = 0
+		b.saved = 0
+		b.warned = make(map[string]bool)
+	}
+	if !b.lastSample.IsZero() {
+		b.active += now.Sub(b.lastSample)
+	}
+	b.lastSample = now
+	if b.active.Truncate(time.Minute) != b.saved.Truncate(time.Minute) {
+		b.save()
+	}
+
+	for _, level := range config.AppConfig.DailyBudget.AlertLevels {
+		if level.Enabled && b.active >= level.Threshold && !b.warned[level.Level] {
+			state := &config.HyperfocusState{Level: level.Level, StartTime: now}
+			// Adiado pelo limitador, o nível é tentado de novo na próxima amostra.
+			if !alerts.fire(level, state, b.active).delivered() {
+				continue
+			}
+			log.Printf("Orçamento diário atingido: %s (tempo ativo: %v)", level.Level, b.active.Round(time.Second))
+			b.warned[level.Level] = true
+		}
+	}
+}
+
+// pause interrompe a contagem durante a ociosidade; a próxima amostra ativa
+// recomeça do zero em vez de somar o tempo ocioso.
+func (b *dailyBudget) pause() {
+	b.lastSample = time.Time{}
+}
+
+// save grava o uso do dia se mudou desde a última gravação.
+func (b *dailyBudget) save() {
+	if b.active == b.saved {
+		return
+	}
+	database.SaveDailyUsage(db, b.day, b.active)
+	b.saved = b.active
+}
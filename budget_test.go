@@ -0,0 +1,94 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/brutalzinn/focus-helper/config"
+	"github.com/brutalzinn/focus-helper/database"
+)
+
+func storedUsage(t *testing.T, day string) time.Duration {
+	t.Helper()
+	active, err := database.GetDailyUsage(db, day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return active
+}
+
+func TestDailyBudgetTracksElapsedTime(t *testing.T) {
+	openTestDB(t)
+	config.AppConfig.DailyBudget = config.DailyBudgetConfig{}
+	start := time.Date(2026, 10, 19, 14, 0, 0, 0, time.Local)
+	budget := loadDailyBudget(start)
+
+	budget.track(start)
+	budget.track(start.Add(40 * time.Second))
+	if budget.active != 40*time.Second {
+		t.Errorf("ativo %v depois de 40s, esperado 40s", budget.active)
+	}
+	if got := storedUsage(t, budget.day); got != 0 {
+		t.Errorf("uso gravado %v antes do primeiro minuto", got)
+	}
+
+	// A ociosidade não entra no orçamento.
+	budget.pause()
+	budget.track(start.Add(10 * time.Minute))
+	budget.track(start.Add(10*time.Minute + 30*time.Second))
+	if budget.active != 70*time.Second {
+		t.Errorf("ativo %v depois da pausa, esperado 1m10s", budget.active)
+	}
+	if got := storedUsage(t, budget.day); got != 70*time.Second {
+		t.Errorf("uso gravado %v ao completar o minuto, esperado 1m10s", got)
+	}
+
+	budget.track(start.Add(10*time.Minute + 45*time.Second))
+	if got := storedUsage(t, budget.day); got != 70*time.Second {
+		t.Errorf("uso gravado %v no meio do minuto, esperado o valor anterior", got)
+	}
+	budget.save()
+	if got := storedUsage(t, budget.day); got != 85*time.Second {
+		t.Errorf("uso gravado %v no encerramento, esperado 1m25s", got)
+	}
+}
+
+func TestDailyBudgetSavesOnNewDay(t *testing.T) {
+	openTestDB(t)
+	config.AppConfig.DailyBudget = config.DailyBudgetConfig{}
+	evening := time.Date(2026, 10, 19, 23, 59, 0, 0, time.Local)
+	budget := loadDailyBudget(evening)
+	day := budget.day
+
+	budget.track(evening)
+	budget.track(evening.Add(50 * time.Second))
+	budget.pause()
+	budget.track(evening.Add(2 * time.Minute))
+	if got := storedUsage(t, day); got != 50*time.Second {
+		t.Errorf("uso gravado %v para o dia anterior, esperado 50s", got)
+	}
+	if budget.day == day || budget.active != 0 {
+		t.Errorf("orçamento não reiniciou no novo dia: %s %v", budget.day, budget.active)
+	}
+}
+
+func TestDailyBudgetRetriesSuppressedLevel(t *testing.T) {
+	openTestDB(t)
+	newTestAlerts(t, config.RateLimitConfig{Enabled: true, MaxPerHour: 1})
+	alerts.limiter.history = []time.Time{time.Now()}
+	config.AppConfig.DailyBudget = config.DailyBudgetConfig{AlertLevels: []config.AlertLevel{
+		{Enabled: true, Level: "DAILY_HIGH", Severity: config.SeverityHigh, Threshold: time.Minute},
+	}}
+	start := time.Now()
+	budget := loadDailyBudget(start)
+	budget.track(start)
+	budget.track(start.Add(2 * time.Minute))
+	if budget.warned["DAILY_HIGH"] {
+		t.Fatal("nível do orçamento adiado pelo limitador foi marcado como disparado")
+	}
+	alerts.limiter.history = nil
+	budget.track(start.Add(3 * time.Minute))
+	if !budget.warned["DAILY_HIGH"] {
+		t.Error("nível do orçamento não disparou depois de liberado o limite")
+	}
+}
@@ -2,6 +2,8 @@ package config
 
 import (
 	"log"
+	"os"
+	"strings"
 	"time"
 )
 
@@ -14,11 +16,21 @@ const (
 	ActionSound         ActionType = "SOUND"
 	ActionATC           ActionType = "ATC_VOICE"
 	ActionHomeAssistant ActionType = "HOME_ASSISTANT"
+	ActionWebhook       ActionType = "WEBHOOK"
+	ActionExec          ActionType = "EXEC"
+	ActionLockScreen    ActionType = "LOCK_SCREEN"
+	ActionBreakOverlay  ActionType = "BREAK_OVERLAY"
+	ActionSuspendApps   ActionType = "SUSPEND_APPS"
+	ActionMQTT          ActionType = "MQTT"
+	ActionNotify        ActionType = "NOTIFY"
+	ActionEmail         ActionType = "EMAIL"
+	ActionNtfy          ActionType = "NTFY"
 )
 
 type ActionConfig struct {
 	Type             ActionType          `json:"type"`
-	RandomChance     float64             `json:"random_chance,omitempty"`
+	RandomChance     float64             `json:"random_chance,omitempty"` // probabilidade de execução entre 0 e 1 (0 = sempre)
+	Conditions       ActionConditions    `json:"conditions,omitempty"`
 	BackgroundVolume float64             `json:"background_volume,omitempty"`
 	BackgroundFile   string              `json:"background_file,omitempty"`
 	VoiceVolume      float64             `json:"voice_volume,omitempty"`
@@ -26,16 +38,81 @@ type ActionConfig struct {
 	SoundFile        string              `json:"sound_file,omitempty"`
 	PopupTitle       string              `json:"popup_title,omitempty"`
 	PopupMessage     string              `json:"popup_message,omitempty"`
+	PopupAcknowledge bool                `json:"popup_acknowledge,omitempty"` // pergunta se o usuário vai pausar; "Sim" reconhece o alerta
 	HomeAssistant    HomeAssistantConfig `json:"home_assistant,omitempty"`
+	Webhook          WebhookConfig       `json:"webhook,omitempty"`
+	Exec             ExecConfig          `json:"exec,omitempty"`
+	LockScreen       LockScreenConfig    `json:"lock_screen,omitempty"`
+	BreakOverlay     BreakOverlayConfig  `json:"break_overlay,omitempty"`
+	SuspendApps      SuspendAppsConfig   `json:"suspend_apps,omitempty"`
+	MQTT             MQTTActionConfig    `json:"mqtt,omitempty"`
+	Notify           NotifyConfig        `json:"notify,omitempty"`
+	Email            EmailConfig         `json:"email,omitempty"`
+	Ntfy             NtfyConfig          `json:"ntfy,omitempty"`
+	Plugin           map[string]any      `json:"plugin,omitempty"` // configuração opaca repassada ao plugin que atende o tipo
+}
+
+// ActionConditions restringe quando uma ação é executada. Condições vazias
+// são ignoradas; todas as definidas precisam ser satisfeitas.
+type ActionConditions struct {
+	MinRepeat           int            `json:"min_repeat,omitempty"`            // executa a partir deste ciclo de repetição do nível
+	OutsideQuietHours   bool           `json:"outside_quiet_hours,omitempty"`   // não executa durante o horário silencioso
+	LastWellbeingAnswer string         `json:"last_wellbeing_answer,omitempty"` // exige a última resposta de bem-estar ("Sim" ou "Não")
+	Weekdays            []time.Weekday `json:"weekdays,omitempty"`              // executa apenas nestes dias da semana
+}
+
+// QuietHoursConfig define o horário silencioso, a partir da meia-noite.
+// Um intervalo com Start maior que End atravessa a meia-noite.
+type QuietHoursConfig struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// Contains indica se o horário de t está dentro do horário silencioso.
+func (q QuietHoursConfig) Contains(t time.Time) bool {
+	if q.Start == q.End {
+		return false
+	}
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	offset := t.Sub(midnight)
+	if q.Start < q.End {
+		return offset >= q.Start && offset < q.End
+	}
+	return offset >= q.Start || offset < q.End
 }
 
 type AlertLevel struct {
 	Enabled              bool
 	Level                string
+	Severity             int     // SeverityLow a SeverityCritical; 0 deriva a severidade do nome do nível
 	Multiplier           float64 `json:"multiplier,omitempty"`
 	Threshold            time.Duration
+	PlanOffset           time.Duration // com um plano de voo ativo, o limite passa a ser o fim planejado + PlanOffset
+	PreAlertLead         time.Duration // antecedência do aviso silencioso antes do alerta (0 = sem aviso)
 	TriggerHomeAssistant bool
 	Actions              []ActionConfig
+	Steps                []ActionStep // sequência explícita; quando definida, Actions é ignorado
+}
+
+// ActionStep é uma etapa da sequência de um nível de alerta. As ações de uma
+// mesma etapa rodam em paralelo.
+type ActionStep struct {
+	Delay        time.Duration // espera antes de iniciar a etapa
+	WaitPrevious bool          // aguarda a etapa anterior terminar antes de iniciar
+	Actions      []ActionConfig
+}
+
+// Sequence retorna as etapas do nível. Sem Steps, cada ação de Actions vira
+// uma etapa independente e todas rodam em paralelo.
+func (l AlertLevel) Sequence() []ActionStep {
+	if len(l.Steps) > 0 {
+		return l.Steps
+	}
+	steps := make([]ActionStep, 0, len(l.Actions))
+	for _, action := range l.Actions {
+		steps = append(steps, ActionStep{Actions: []ActionConfig{action}})
+	}
+	return steps
 }
 
 type LlamaConfig struct {
@@ -43,15 +120,299 @@ type LlamaConfig struct {
 	BasePrompt string `json:"base_prompt"`
 }
 
+type TTSProviderType string
+
+const (
+	TTSPiper    TTSProviderType = "PIPER"
+	TTSEspeakNG TTSProviderType = "ESPEAK_NG"
+	TTSGoogle   TTSProviderType = "GOOGLE"
+)
+
+// TTSConfig escolhe os motores de síntese de voz das transmissões ATC. Order é
+// a ordem de preferência: quando o binário, o modelo ou o serviço de um motor
+// não está disponível, o seguinte é usado.
+type TTSConfig struct {
+	Order    []TTSProviderType
+	Piper    TTSVoiceConfig // Voice: modelo em voices/, sem a extensão .onnx
+	EspeakNG TTSVoiceConfig // Voice: voz do espeak-ng, ex.: pt-br
+	Google   GoogleTTSConfig
+}
+
+// TTSVoiceConfig define a voz de um motor. Rate e Pitch são multiplicadores
+// (1.0 = natural); zero usa o padrão.
+type TTSVoiceConfig struct {
+	Voice string
+	Rate  float64
+	Pitch float64
+}
+
+// GoogleTTSConfig configura o Google Cloud Text-to-Speech. Endpoint aponta
+// para outro servidor gRPC, como um substituto local em testes; Insecure
+// dispensa TLS e credenciais nessa conexão.
+type GoogleTTSConfig struct {
+	TTSVoiceConfig        // Voice: nome da voz do Google, ex.: pt-BR-Wavenet-B
+	Endpoint       string // padrão: texttospeech.googleapis.com:443
+	Insecure       bool
+	Timeout        time.Duration // padrão: 10s, para não atrasar o fallback
+}
+
 type HomeAssistantConfig struct {
 	Enabled    bool   `json:"enabled"`
 	WebhookURL string `json:"webhook_url"`
 }
 
+// BreakPolicyConfig define quanto tempo de pausa é exigido para zerar uma
+// sessão de foco. A pausa exigida cresce com o tamanho da sessão.
+type BreakPolicyConfig struct {
+	BreakPerHour time.Duration // pausa exigida para cada hora de foco contínuo
+	MinBreak     time.Duration // pausa mínima, independente do tamanho da sessão
+	MaxBreak     time.Duration // teto da pausa exigida (0 = sem teto)
+}
+
+// DailyBudgetConfig define um orçamento de tempo ativo acumulado no dia,
+// independente das sessões contínuas.
+type DailyBudgetConfig struct {
+	Enabled     bool
+	DayBoundary time.Duration // horário de virada do dia, a partir da meia-noite (ex.: 4h = 04:00)
+	AlertLevels []AlertLevel  // o Threshold de cada nível é o tempo ativo acumulado no dia
+}
+
+// PomodoroConfig define o modo de intervalos fixos de trabalho e pausa, que
+// substitui a detecção de hiperfoco quando habilitado.
+type PomodoroConfig struct {
+	Enabled               bool
+	WorkDuration          time.Duration
+	ShortBreak            time.Duration
+	LongBreak             time.Duration
+	CyclesBeforeLongBreak int
+	BreakGrace            time.Duration // tolerância de atividade no início da pausa
+	CalloutInterval       time.Duration // intervalo mínimo entre chamadas da torre durante a pausa
+	WorkStart             AlertLevel    // "autorizado para decolagem"
+	BreakStart            AlertLevel    // "inicie a descida"
+	BreakViolation        AlertLevel    // atividade detectada durante a pausa
+}
+
+// WebhookConfig define uma requisição HTTP genérica. O corpo é um template Go
+// que recebe os dados do evento de alerta; vazio envia o evento em JSON.
+type WebhookConfig struct {
+	URL           string            `json:"url"`
+	Method        string            `json:"method,omitempty"` // padrão POST
+	Headers       map[string]string `json:"headers,omitempty"`
+	BearerToken   string            `json:"bearer_token,omitempty"`
+	BasicUser     string            `json:"basic_user,omitempty"`
+	BasicPassword string            `json:"basic_password,omitempty"`
+	BodyTemplate  string            `json:"body_template,omitempty"`
+	Retries       int               `json:"retries,omitempty"`     // novas tentativas após a primeira falha
+	RetryDelay    time.Duration     `json:"retry_delay,omitempty"` // padrão 2s, dobra a cada tentativa
+	Timeout       time.Duration     `json:"timeout,omitempty"`     // padrão 10s por tentativa
+}
+
+// ExecConfig define um comando executado no alerta. O evento é passado em
+// variáveis FOCUS_* e como JSON na entrada padrão.
+type ExecConfig struct {
+	Command []string      `json:"command"` // argv; o primeiro item é o executável
+	Dir     string        `json:"dir,omitempty"`
+	Timeout time.Duration `json:"timeout,omitempty"` // padrão 30s
+}
+
+// LockScreenConfig define o bloqueio de tela com contagem regressiva.
+type LockScreenConfig struct {
+	GracePeriod     time.Duration `json:"grace_period,omitempty"`      // contagem regressiva antes do bloqueio (padrão 60s)
+	WarningInterval time.Duration `json:"warning_interval,omitempty"`  // intervalo entre avisos da contagem (padrão 15s)
+	FallbackCommand []string      `json:"fallback_command,omitempty"`  // usado quando o logind não está disponível
+	EnforceMinBreak bool          `json:"enforce_min_break,omitempty"` // a pausa só conta depois da pausa mínima
+	MinBreak        time.Duration `json:"min_break,omitempty"`         // pausa mínima; 0 usa a pausa exigida pela BreakPolicy
+	BusAddress      string        `json:"bus_address,omitempty"`       // barramento D-Bus do logind (padrão: barramento do sistema)
+}
+
+// BreakOverlayConfig define a janela de pausa em tela cheia.
+type BreakOverlayConfig struct {
+	Title         string        `json:"title,omitempty"`
+	Message       string        `json:"message,omitempty"`        // padrão: a mensagem ATC do nível
+	BreakDuration time.Duration `json:"break_duration,omitempty"` // 0 usa a pausa exigida pela BreakPolicy
+	ConfirmPhrase string        `json:"confirm_phrase,omitempty"` // frase para sair sem pausa (padrão "assumo o controle")
+}
+
+// SuspendAppsConfig define os processos parados com SIGSTOP durante a pausa.
+// Os padrões são expressões regulares aplicadas ao nome e à linha de comando.
+type SuspendAppsConfig struct {
+	Match     []string      `json:"match"`
+	Allowlist []string      `json:"allowlist,omitempty"` // processos que nunca são suspensos
+	Duration  time.Duration `json:"duration,omitempty"`  // 0 usa a pausa exigida pela BreakPolicy
+}
+
+// MQTTConfig define o broker MQTT usado pelas ações MQTT e pelo tópico de
+// estado retido focus-helper/<host>/state.
+type MQTTConfig struct {
+	Enabled    bool   // publica o tópico de estado
+	Broker     string // ex.: tcp://localhost:1883
+	ClientID   string // padrão focus-helper-<host>
+	Username   string
+	Password   string
+	StateTopic string // padrão focus-helper/<host>/state
+	StateQoS   byte
+}
+
+// WithDefaults preenche o ClientID e o tópico de estado a partir do hostname.
+func (c MQTTConfig) WithDefaults() MQTTConfig {
+	host, err := os.Hostname()
+	if err != nil || host == "" {
+		host = "localhost"
+	}
+	if c.ClientID == "" {
+		c.ClientID = "focus-helper-" + host
+	}
+	if c.StateTopic == "" {
+		c.StateTopic = "focus-helper/" + host + "/state"
+	}
+	return c
+}
+
+// MQTTActionConfig define uma publicação MQTT no alerta. O payload é um
+// template Go que recebe os dados do evento; vazio publica o evento em JSON.
+type MQTTActionConfig struct {
+	Broker          string `json:"broker,omitempty"` // padrão: o broker de Config.MQTT
+	Topic           string `json:"topic"`            // template Go, ex.: focus-helper/alerts/{{.Level}}
+	QoS             byte   `json:"qos,omitempty"`
+	Retain          bool   `json:"retain,omitempty"`
+	PayloadTemplate string `json:"payload_template,omitempty"`
+}
+
+// NotifyButtonAction é o efeito de um botão da notificação.
+type NotifyButtonAction string
+
+const (
+	NotifyAcknowledge NotifyButtonAction = "ACKNOWLEDGE" // encerra o alerta em andamento
+	NotifySnooze      NotifyButtonAction = "SNOOZE"      // adia os alertas por Snooze
+)
+
+type NotifyButton struct {
+	Label  string             `json:"label"`
+	Action NotifyButtonAction `json:"action"`
+	Snooze time.Duration      `json:"snooze,omitempty"` // duração da soneca (padrão 10min)
+}
+
+// NotifyConfig define uma notificação de desktop com botões, enviada pelo
+// org.freedesktop.Notifications. Título e mensagem são templates Go.
+type NotifyConfig struct {
+	Title      string         `json:"title,omitempty"`   // padrão "Focus Helper"
+	Message    string         `json:"message,omitempty"` // padrão: a mensagem ATC do nível
+	Urgency    string         `json:"urgency,omitempty"` // low, normal ou critical; padrão: derivada do nível
+	Buttons    []NotifyButton `json:"buttons,omitempty"` // padrão: "Fazendo uma pausa" e "Adiar 10 min"
+	Timeout    time.Duration  `json:"timeout,omitempty"`
+	BusAddress string         `json:"bus_address,omitempty"` // barramento de sessão (padrão: DBUS_SESSION_BUS_ADDRESS)
+}
+
+// SMTPConfig define o servidor usado pelas ações EMAIL e pelo resumo diário.
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string // vazio envia sem autenticação
+	Password string
+	From     string // ex.: Focus Helper <focus@exemplo.com>
+	StartTLS bool   // exige STARTTLS; desative só para servidores locais de captura
+}
+
+// EmailConfig define um e-mail enviado no alerta. Assunto e corpo são
+// templates Go que recebem os dados do evento.
+type EmailConfig struct {
+	To      []string `json:"to"`
+	Subject string   `json:"subject,omitempty"` // padrão "Focus Helper: alerta {{.Level}}"
+	Body    string   `json:"body,omitempty"`    // padrão: mensagem, duração da sessão e tarefa
+}
+
+// DailyDigestConfig define o resumo diário por e-mail com as sessões, os
+// alertas e as respostas de bem-estar do dia.
+type DailyDigestConfig struct {
+	Enabled bool
+	SendAt  time.Duration // horário de envio, a partir da meia-noite
+	To      []string
+	Subject string // template Go; padrão "Focus Helper: resumo de {{.Day}}"
+	Body    string // template Go; vazio usa o resumo padrão
+}
+
+// NtfyConfig define uma notificação push no estilo ntfy.sh. Título e mensagem
+// são templates Go que recebem os dados do evento.
+type NtfyConfig struct {
+	Server   string   `json:"server,omitempty"` // padrão https://ntfy.sh
+	Topic    string   `json:"topic"`
+	Title    string   `json:"title,omitempty"`
+	Message  string   `json:"message,omitempty"`  // padrão: a mensagem ATC do nível
+	Priority int      `json:"priority,omitempty"` // 1 (mínima) a 5 (máxima); 0 usa o padrão do servidor
+	Tags     []string `json:"tags,omitempty"`
+	Token    string   `json:"token,omitempty"` // token de acesso, enviado como Bearer
+}
+
+// EscalationConfig define o aviso a um contato de confiança quando um alerta
+// crítico é ignorado: nem pausa nem reconhecimento dentro de After.
+type EscalationConfig struct {
+	Enabled     bool
+	Levels      []string      // níveis que iniciam a escalada
+	After       time.Duration // tempo sem pausa ou reconhecimento antes de avisar o contato
+	MinInterval time.Duration // intervalo mínimo entre duas escaladas
+	Alert       AlertLevel    // ações que avisam o contato (WEBHOOK, EMAIL ou NTFY)
+}
+
+// Severidade dos níveis de alerta, usada pelo limitador de interrupções e pela
+// urgência das notificações.
+const (
+	SeverityLow = iota + 1
+	SeverityMedium
+	SeverityHigh
+	SeverityCritical
+)
+
+// LevelSeverity retorna a severidade configurada do nível. Sem Severity, ela é
+// derivada do sufixo do nome (LOW, MEDIUM, HIGH ou CRITICAL); níveis sem
+// sufixo conhecido são tratados como MEDIUM.
+func LevelSeverity(level AlertLevel) int {
+	if level.Severity != 0 {
+		return level.Severity
+	}
+	switch name := level.Level; {
+	case strings.HasSuffix(name, "CRITICAL"):
+		return SeverityCritical
+	case strings.HasSuffix(name, "HIGH"):
+		return SeverityHigh
+	case strings.HasSuffix(name, "LOW"):
+		return SeverityLow
+	default:
+		return SeverityMedium
+	}
+}
+
+// RateLimitConfig limita as interrupções de todas as fontes: níveis de alerta,
+// orçamento diário, pomodoro e perguntas de bem-estar.
+type RateLimitConfig struct {
+	Enabled        bool
+	MaxPerHour     int           // interrupções por hora; níveis críticos passam, mas contam
+	MergeWindow    time.Duration // novos disparos do mesmo nível dentro da janela são agrupados
+	MaxRepetitions int           // teto de repetições de áudio por alerta (0 = sem teto)
+}
+
+// RepeatCap retorna o teto de repetições por alerta, ou 0 quando o limitador
+// está desligado.
+func (c RateLimitConfig) RepeatCap() int {
+	if !c.Enabled {
+		return 0
+	}
+	return c.MaxRepetitions
+}
+
+// RenderCacheConfig controla o cache em disco das transmissões ATC já
+// renderizadas, indexado pelo texto, voz, cadeia de efeitos e volumes.
+type RenderCacheConfig struct {
+	Enabled   bool
+	Dir       string // padrão: focus-helper/radio no cache do usuário
+	MaxBytes  int64  // tamanho máximo; os arquivos menos usados são removidos antes
+	PreRender bool   // gera pelo LLM na inicialização, e renova a cada uso, uma transmissão por prompt ATC
+}
+
 type HyperfocusState struct {
 	Level     string    /// determina o nivel do hiperfoco
 	StartTime time.Time /// hora de inicio do hiperfoco
 	EndTime   time.Time /// hora de fim do hiperfoco
+	Task      string    /// tarefa declarada no plano de voo ativo, se houver
 }
 type MiscConfig struct {
 	WarnedThresholds       map[time.Duration]bool
@@ -60,16 +421,32 @@ type MiscConfig struct {
 
 type Config struct {
 	DEBUG                     bool
+	Profile                   string
 	IdleTimeout               time.Duration
 	ActivityCheckRate         time.Duration
 	MinRandomQuestion         time.Duration
 	MaxRandomQuestion         time.Duration
 	DatabaseFile              string
 	LogFile                   string
+	StatusFile                string
+	SuspendedAppsFile         string   // registro dos processos suspensos, retomados após um crash
+	DuckedStreamsFile         string   // registro dos volumes reduzidos, restaurados após um crash
+	PluginDirs                []string // diretórios com executáveis de plugin de ações
 	Llama                     LlamaConfig
+	TTS                       TTSConfig
 	HomeAssistant             HomeAssistantConfig
+	MQTT                      MQTTConfig
+	SMTP                      SMTPConfig
+	DailyDigest               DailyDigestConfig
+	Escalation                EscalationConfig
+	RateLimit                 RateLimitConfig
+	RenderCache               RenderCacheConfig
 	WellbeingQuestionsEnabled bool
 	ReduceOSSounds            bool
+	QuietHours                QuietHoursConfig
+	BreakPolicy               BreakPolicyConfig
+	DailyBudget               DailyBudgetConfig
+	Pomodoro                  PomodoroConfig
 	Misc                      MiscConfig
 	AlertLevels               []AlertLevel
 }
@@ -91,6 +468,7 @@ func Init(debugMode bool) Config {
 func loadProdConfig() Config {
 	return Config{
 		DEBUG:                     false,
+		Profile:                   "production",
 		IdleTimeout:               3 * time.Minute,  // Tempo para considerar o usuário ocioso
 		ActivityCheckRate:         30 * time.Second, // Verificação menos frequente para economizar recursos
 		ReduceOSSounds:            true,
@@ -99,24 +477,143 @@ func loadProdConfig() Config {
 		MaxRandomQuestion:         90 * time.Minute,
 		DatabaseFile:              "./focus_helper.db",
 		LogFile:                   "./focus_helper.log",
+		StatusFile:                "./focus_helper_status.json",
+		SuspendedAppsFile:         "./focus_helper_suspended.json",
+		DuckedStreamsFile:         "./focus_helper_ducked.json",
+		PluginDirs:                []string{"./focus_helper_plugins"},
 		Llama: LlamaConfig{
 			Model: "llama3.2:latest",
 		},
+		TTS: TTSConfig{
+			Order:    []TTSProviderType{TTSPiper, TTSEspeakNG},
+			Piper:    TTSVoiceConfig{Voice: "pt_BR-cadu-medium"},
+			EspeakNG: TTSVoiceConfig{Voice: "pt-br"},
+			Google:   GoogleTTSConfig{TTSVoiceConfig: TTSVoiceConfig{Voice: "pt-BR-Wavenet-B"}},
+		},
+		MQTT: MQTTConfig{
+			Enabled: false,
+			Broker:  "tcp://localhost:1883",
+		},
+		SMTP: SMTPConfig{
+			Host:     "localhost",
+			Port:     587,
+			From:     "Focus Helper <focus-helper@localhost>",
+			StartTLS: true,
+		},
+		DailyDigest: DailyDigestConfig{
+			Enabled: false,
+			SendAt:  21 * time.Hour,
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:        true,
+			MaxPerHour:     6,
+			MergeWindow:    2 * time.Minute,
+			MaxRepetitions: 3,
+		},
+		RenderCache: RenderCacheConfig{
+			Enabled:   true,
+			MaxBytes:  200 << 20,
+			PreRender: false,
+		},
+		Escalation: EscalationConfig{
+			Enabled:     false,
+			Levels:      []string{"CRITICAL"},
+			After:       15 * time.Minute,
+			MinInterval: 4 * time.Hour,
+			Alert: AlertLevel{
+				Level: "ESCALATION",
+				Actions: []ActionConfig{
+					{Type: ActionNtfy, Ntfy: NtfyConfig{Topic: "", Title: "Focus Helper: alerta crítico ignorado", Priority: 4, Tags: []string{"warning"},
+						Message: "Sessão de foco de {{.SessionMinutes}} minutos sem pausa, mesmo após o alerta crítico. Que tal mandar uma mensagem?"}},
+				},
+			},
+		},
+		QuietHours: QuietHoursConfig{
+			Start: 22 * time.Hour,
+			End:   7 * time.Hour,
+		},
+		BreakPolicy: BreakPolicyConfig{
+			BreakPerHour: 5 * time.Minute,
+			MinBreak:     5 * time.Minute,
+			MaxBreak:     30 * time.Minute,
+		},
+		DailyBudget: DailyBudgetConfig{
+			Enabled:     true,
+			DayBoundary: 4 * time.Hour,
+			AlertLevels: []AlertLevel{
+				{
+					Enabled:   true,
+					Level:     "DAILY_HIGH",
+					Severity:  SeverityHigh,
+					Threshold: 8 * time.Hour,
+					Actions: []ActionConfig{
+						{Type: ActionSound, SoundFile: "alert_level_3.mp3"},
+						{Type: ActionATC, VoiceVolume: 1.0, BackgroundVolume: 0.5, BackgroundFile: "radio_static.wav", LlamaPrompt: "Piloto-Alfa-Um, você já acumulou oito horas de voo hoje. Planeje o encerramento da jornada."},
+					},
+				},
+				{
+					Enabled:    true,
+					Level:      "DAILY_CRITICAL",
+					Severity:   SeverityCritical,
+					Threshold:  10 * time.Hour,
+					Multiplier: 2.0,
+					Actions: []ActionConfig{
+						{Type: ActionPopup, PopupTitle: "Limite diário atingido", PopupMessage: "Você ultrapassou o limite diário de tempo de tela. Encerre as atividades por hoje."},
+						{Type: ActionATC, VoiceVolume: 1.0, BackgroundVolume: 0.5, BackgroundFile: "radio_static.wav", LlamaPrompt: "Piloto-Alfa-Um, limite diário de horas de voo excedido. Pouse imediatamente e encerre a jornada."},
+					},
+				},
+			},
+		},
+		Pomodoro: PomodoroConfig{
+			WorkDuration:          25 * time.Minute,
+			ShortBreak:            5 * time.Minute,
+			LongBreak:             15 * time.Minute,
+			CyclesBeforeLongBreak: 4,
+			BreakGrace:            30 * time.Second,
+			CalloutInterval:       time.Minute,
+			WorkStart: AlertLevel{
+				Level:    "POMODORO_WORK",
+				Severity: SeverityMedium,
+				Actions: []ActionConfig{
+					{Type: ActionATC, VoiceVolume: 1.0, BackgroundVolume: 0.3, BackgroundFile: "radio_static.wav", LlamaPrompt: "Piloto-Alfa-Um, autorizado para decolagem. Início de um novo ciclo de foco."},
+				},
+			},
+			BreakStart: AlertLevel{
+				Level:    "POMODORO_BREAK",
+				Severity: SeverityMedium,
+				Actions: []ActionConfig{
+					{Type: ActionSound, SoundFile: "autopilot.mp3"},
+					{Type: ActionATC, VoiceVolume: 1.0, BackgroundVolume: 0.3, BackgroundFile: "radio_static.wav", LlamaPrompt: "Piloto-Alfa-Um, inicie a descida. Ciclo de foco concluído, afaste-se do computador para a pausa."},
+				},
+			},
+			BreakViolation: AlertLevel{
+				Level:    "POMODORO_BREAK_VIOLATION",
+				Severity: SeverityMedium,
+				Actions: []ActionConfig{
+					{Type: ActionATC, VoiceVolume: 1.0, BackgroundVolume: 0.5, BackgroundFile: "radio_static.wav", LlamaPrompt: "Piloto-Alfa-Um, a torre detectou atividade durante a pausa. Afaste-se dos controles até o fim da pausa."},
+				},
+			},
+		},
 		AlertLevels: []AlertLevel{
 			{
-				Enabled:   true,
-				Level:     "LOW",
-				Threshold: 45 * time.Minute,
+				Enabled:      true,
+				Level:        "LOW",
+				Severity:     SeverityLow,
+				Threshold:    45 * time.Minute,
+				PreAlertLead: 5 * time.Minute,
 				Actions: []ActionConfig{
 					{Type: ActionSound, SoundFile: "alert_level_1.mp3"},
 					{Type: ActionATC, VoiceVolume: 1.0, BackgroundVolume: 0.3, BackgroundFile: "radio_static.wav", LlamaPrompt: "Piloto-Alfa-Um, aqui é a Torre. Apenas um lembrete para verificar seus sistemas e fazer uma pequena pausa, se necessário."},
 				},
 			},
 			{
-				Enabled:    true,
-				Level:      "MEDIUM",
-				Threshold:  90 * time.Minute,
-				Multiplier: 1.5,
+				Enabled:      true,
+				Level:        "MEDIUM",
+				Severity:     SeverityMedium,
+				Threshold:    90 * time.Minute,
+				PlanOffset:   15 * time.Minute,
+				PreAlertLead: 5 * time.Minute,
+				Multiplier:   1.5,
 				Actions: []ActionConfig{
 					{Type: ActionSound, SoundFile: "autopilot.mp3"},
 					{Type: ActionATC, VoiceVolume: 1.0, BackgroundVolume: 0.4, BackgroundFile: "radio_static.wav", LlamaPrompt: "Piloto-Alfa-Um, você está em um longo período de foco. Recomendamos uma pausa para hidratação e alongamento."},
@@ -125,18 +622,23 @@ func loadProdConfig() Config {
 			{
 				Enabled:    true,
 				Level:      "HIGH",
+				Severity:   SeverityHigh,
 				Threshold:  2*time.Hour + 30*time.Minute,
+				PlanOffset: 30 * time.Minute,
 				Multiplier: 2.5,
-				Actions: []ActionConfig{
-					{Type: ActionSound, SoundFile: "alert_level_3.mp3"},
-					{Type: ActionPopup, PopupTitle: "Alerta de Foco Intenso", PopupMessage: "Você está trabalhando continuamente por um longo período. Considere fazer uma pausa mais longa."},
-					{Type: ActionATC, VoiceVolume: 1.0, BackgroundVolume: 0.5, BackgroundFile: "radio_static.wav", LlamaPrompt: "Piloto-Alfa-Um, detectamos sinais de hiperfoco. É crucial fazer uma pausa para manter a performance e o bem-estar."},
+				// Toca o alarme, depois a chamada da torre e só então mostra o popup.
+				Steps: []ActionStep{
+					{Actions: []ActionConfig{{Type: ActionSound, SoundFile: "alert_level_3.mp3"}}},
+					{WaitPrevious: true, Actions: []ActionConfig{{Type: ActionATC, VoiceVolume: 1.0, BackgroundVolume: 0.5, BackgroundFile: "radio_static.wav", LlamaPrompt: "Piloto-Alfa-Um, detectamos sinais de hiperfoco. É crucial fazer uma pausa para manter a performance e o bem-estar."}}},
+					{WaitPrevious: true, Actions: []ActionConfig{{Type: ActionPopup, PopupTitle: "Alerta de Foco Intenso", PopupMessage: "Você está trabalhando continuamente por um longo período. Considere fazer uma pausa mais longa."}}},
 				},
 			},
 			{
 				Enabled:    true,
 				Level:      "CRITICAL",
+				Severity:   SeverityCritical,
 				Threshold:  4 * time.Hour,
+				PlanOffset: time.Hour,
 				Multiplier: 5.0,
 				Actions: []ActionConfig{
 					{Type: ActionATC, VoiceVolume: 1.0, BackgroundVolume: 1.0, BackgroundFile: "radio_static.wav", LlamaPrompt: "Mayday, Mayday, Mayday. Piloto-Alfa-Um, risco de burnout detectado. Desligue o piloto automático e faça uma pausa obrigatória imediatamente."}, // <-- Adicionado VoiceVolume
@@ -148,6 +650,7 @@ func loadProdConfig() Config {
 func loadDebugConfig() Config {
 	return Config{
 		DEBUG:             true,
+		Profile:           "debug",
 		IdleTimeout:       30 * time.Second,
 		ActivityCheckRate: 5 * time.Second,
 		MinRandomQuestion: 30 * time.Second,
@@ -157,15 +660,113 @@ func loadDebugConfig() Config {
 			Model:      "llama3.2:latest",
 			BasePrompt: "Piloto-Alfa-Um, você está em uma missão de foco intenso. Mantenha a calma e siga as instruções da torre.",
 		},
+		TTS: TTSConfig{
+			Order:    []TTSProviderType{TTSPiper, TTSEspeakNG},
+			Piper:    TTSVoiceConfig{Voice: "pt_BR-cadu-medium"},
+			EspeakNG: TTSVoiceConfig{Voice: "pt-br"},
+			Google:   GoogleTTSConfig{TTSVoiceConfig: TTSVoiceConfig{Voice: "pt-BR-Wavenet-B"}},
+		},
+		MQTT: MQTTConfig{
+			Enabled: false,
+			Broker:  "tcp://localhost:1883",
+		},
+		SMTP: SMTPConfig{
+			Host: "localhost",
+			Port: 1025, // servidor local de captura, ex.: mailpit
+			From: "Focus Helper <focus-helper@localhost>",
+		},
+		DailyDigest: DailyDigestConfig{
+			Enabled: false,
+			SendAt:  21 * time.Hour,
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:        true,
+			MaxPerHour:     60,
+			MergeWindow:    10 * time.Second,
+			MaxRepetitions: 2,
+		},
+		RenderCache: RenderCacheConfig{
+			Enabled:   true,
+			MaxBytes:  50 << 20,
+			PreRender: false,
+		},
+		Escalation: EscalationConfig{
+			Enabled:     false,
+			Levels:      []string{"CRITICAL"},
+			After:       time.Minute,
+			MinInterval: 5 * time.Minute,
+			Alert: AlertLevel{
+				Level: "ESCALATION",
+				Actions: []ActionConfig{
+					{Type: ActionNtfy, Ntfy: NtfyConfig{Topic: "", Title: "Focus Helper: alerta crítico ignorado", Priority: 4, Tags: []string{"warning"},
+						Message: "Sessão de foco de {{.SessionMinutes}} minutos sem pausa, mesmo após o alerta crítico. Que tal mandar uma mensagem?"}},
+				},
+			},
+		},
+		BreakPolicy: BreakPolicyConfig{
+			BreakPerHour: 30 * time.Minute,
+			MinBreak:     20 * time.Second,
+			MaxBreak:     2 * time.Minute,
+		},
+		DailyBudget: DailyBudgetConfig{
+			Enabled:     true,
+			DayBoundary: 4 * time.Hour,
+			AlertLevels: []AlertLevel{
+				{
+					Enabled:   true,
+					Level:     "DAILY_CRITICAL",
+					Severity:  SeverityCritical,
+					Threshold: 90 * time.Second,
+					Actions: []ActionConfig{
+						{Type: ActionATC, VoiceVolume: 1.0, BackgroundFile: "radio_static.wav", BackgroundVolume: 1.0, LlamaPrompt: "Piloto-Alfa-Um, limite diário de horas de voo excedido."},
+					},
+				},
+			},
+		},
+		Pomodoro: PomodoroConfig{
+			WorkDuration:          30 * time.Second,
+			ShortBreak:            15 * time.Second,
+			LongBreak:             30 * time.Second,
+			CyclesBeforeLongBreak: 2,
+			BreakGrace:            5 * time.Second,
+			CalloutInterval:       10 * time.Second,
+			WorkStart: AlertLevel{
+				Level:    "POMODORO_WORK",
+				Severity: SeverityMedium,
+				Actions: []ActionConfig{
+					{Type: ActionATC, VoiceVolume: 1.0, BackgroundFile: "radio_static.wav", BackgroundVolume: 1.0, LlamaPrompt: "Piloto-Alfa-Um, autorizado para decolagem."},
+				},
+			},
+			BreakStart: AlertLevel{
+				Level:    "POMODORO_BREAK",
+				Severity: SeverityMedium,
+				Actions: []ActionConfig{
+					{Type: ActionATC, VoiceVolume: 1.0, BackgroundFile: "radio_static.wav", BackgroundVolume: 1.0, LlamaPrompt: "Piloto-Alfa-Um, inicie a descida."},
+				},
+			},
+			BreakViolation: AlertLevel{
+				Level:    "POMODORO_BREAK_VIOLATION",
+				Severity: SeverityMedium,
+				Actions: []ActionConfig{
+					{Type: ActionATC, VoiceVolume: 1.0, BackgroundFile: "radio_static.wav", BackgroundVolume: 1.0, LlamaPrompt: "Piloto-Alfa-Um, atividade detectada durante a pausa."},
+				},
+			},
+		},
 		WellbeingQuestionsEnabled: false,
 		DatabaseFile:              "./focus_helper_debug.db",
 		LogFile:                   "./focus_helper_debug.log",
+		StatusFile:                "./focus_helper_debug_status.json",
+		SuspendedAppsFile:         "./focus_helper_debug_suspended.json",
+		DuckedStreamsFile:         "./focus_helper_debug_ducked.json",
+		PluginDirs:                []string{"./focus_helper_plugins"},
 		AlertLevels: []AlertLevel{
 			{
-				Enabled:    true,
-				Level:      "LOW",
-				Threshold:  10 * time.Second,
-				Multiplier: 1.0,
+				Enabled:      true,
+				Level:        "LOW",
+				Severity:     SeverityLow,
+				Threshold:    10 * time.Second,
+				PreAlertLead: 5 * time.Second,
+				Multiplier:   1.0,
 				Actions: []ActionConfig{
 					{Type: ActionSound, SoundFile: "alert_level_1.mp3"},
 					{Type: ActionATC, VoiceVolume: 1.0, BackgroundFile: "radio_static.wav", BackgroundVolume: 1.0, LlamaPrompt: "Piloto-Alfa-Um detecção de Windshear ou hiperfoco próximo."}, // <-- VoiceVolume ajustado
@@ -174,17 +775,23 @@ func loadDebugConfig() Config {
 			{
 				Enabled:    true,
 				Level:      "MEDIUM",
+				Severity:   SeverityMedium,
 				Threshold:  25 * time.Second,
+				PlanOffset: 10 * time.Second,
 				Multiplier: 1.5,
 				Actions: []ActionConfig{
 					{Type: ActionSound, SoundFile: "autopilot.mp3"},
 					{Type: ActionATC, VoiceVolume: 1.2, BackgroundFile: "radio_static.wav", BackgroundVolume: 1.0, LlamaPrompt: "Piloto-Alfa-Um você está em hipertoco. Solicito que siga para o próximo aeroporto cozinha e solicite ajuda."}, // <-- Adicionado VoiceVolume
+					// Exemplo de condições: aparece em cerca de 30% dos disparos, fora do horário silencioso.
+					{Type: ActionPopup, RandomChance: 0.3, Conditions: ActionConditions{OutsideQuietHours: true}, PopupTitle: "Hora de se hidratar", PopupMessage: "Que tal buscar um copo d'água antes de continuar?"},
 				},
 			},
 			{
 				Enabled:    true,
 				Level:      "HIGH",
+				Severity:   SeverityHigh,
 				Threshold:  45 * time.Second,
+				PlanOffset: 20 * time.Second,
 				Multiplier: 2.0,
 				Actions: []ActionConfig{
 					{Type: ActionSound, SoundFile: "alert_level_3.mp3"},
@@ -196,12 +803,16 @@ func loadDebugConfig() Config {
 			{
 				Enabled:    true,
 				Level:      "CRITICAL",
+				Severity:   SeverityCritical,
 				Threshold:  60 * time.Second,
+				PlanOffset: 30 * time.Second,
 				Multiplier: 5.0,
 				Actions: []ActionConfig{
 					{Type: ActionSound, SoundFile: "autopilot.mp3"},
 					{Type: ActionATC, VoiceVolume: 2.0, BackgroundVolume: 1.0, BackgroundFile: "radio_static.wav", LlamaPrompt: "Piloto-Alfa-Um você perdeu o controle. Siga as instruções na tela."},
 					{Type: ActionATC, VoiceVolume: 2.0, BackgroundVolume: 1.0, BackgroundFile: "radio_static.wav", LlamaPrompt: "Piloto-Alfa-Um solicito que desligue o piloto automático e siga as ordens da torre."},
+					// Exemplo de escalonamento: só aparece a partir do segundo ciclo de repetição.
+					{Type: ActionPopup, Conditions: ActionConditions{MinRepeat: 2}, PopupAcknowledge: true, PopupTitle: "Pausa obrigatória", PopupMessage: "A torre ainda não recebeu sua confirmação. Afaste-se do computador agora."},
 				},
 			},
 		},
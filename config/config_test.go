@@ -0,0 +1,34 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuietHoursContains(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2026, 10, 19, hour, minute, 0, 0, time.Local)
+	}
+	tests := []struct {
+		name  string
+		quiet QuietHoursConfig
+		t     time.Time
+		want  bool
+	}{
+		{"desligado", QuietHoursConfig{}, at(3, 0), false},
+		{"mesmo dia, dentro", QuietHoursConfig{Start: 12 * time.Hour, End: 14 * time.Hour}, at(13, 0), true},
+		{"mesmo dia, no início", QuietHoursConfig{Start: 12 * time.Hour, End: 14 * time.Hour}, at(12, 0), true},
+		{"mesmo dia, no fim", QuietHoursConfig{Start: 12 * time.Hour, End: 14 * time.Hour}, at(14, 0), false},
+		{"mesmo dia, fora", QuietHoursConfig{Start: 12 * time.Hour, End: 14 * time.Hour}, at(9, 0), false},
+		{"atravessa a meia-noite, à noite", QuietHoursConfig{Start: 22 * time.Hour, End: 7 * time.Hour}, at(23, 30), true},
+		{"atravessa a meia-noite, de madrugada", QuietHoursConfig{Start: 22 * time.Hour, End: 7 * time.Hour}, at(3, 0), true},
+		{"atravessa a meia-noite, na meia-noite", QuietHoursConfig{Start: 22 * time.Hour, End: 7 * time.Hour}, at(0, 0), true},
+		{"atravessa a meia-noite, no fim", QuietHoursConfig{Start: 22 * time.Hour, End: 7 * time.Hour}, at(7, 0), false},
+		{"atravessa a meia-noite, à tarde", QuietHoursConfig{Start: 22 * time.Hour, End: 7 * time.Hour}, at(15, 0), false},
+	}
+	for _, tt := range tests {
+		if got := tt.quiet.Contains(tt.t); got != tt.want {
+			t.Errorf("%s: Contains(%s) = %v, esperado %v", tt.name, tt.t.Format("15:04"), got, tt.want)
+		}
+	}
+}
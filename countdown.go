@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/brutalzinn/focus-helper/config"
+	"github.com/brutalzinn/focus-helper/notifications"
+	"github.com/brutalzinn/focus-helper/status"
+)
+
+// nextAlertLevel retorna o próximo nível ainda não disparado na sessão e o
+// uso acumulado em que ele dispara.
+func nextAlertLevel(state *AppState) (config.AlertLevel, time.Duration, bool) {
+	var next config.AlertLevel
+	var nextThreshold time.Duration
+	found := false
+	for _, level := range config.AppConfig.AlertLevels {
+		if !level.Enabled || state.warnedThresholds[level.Level] {
+			continue
+		}
+		threshold := levelThreshold(state, level)
+		if !found || threshold < nextThreshold {
+			next, nextThreshold, found = level, threshold, true
+		}
+	}
+	return next, nextThreshold, found
+}
+
+// checkPreAlerts envia um aviso silencioso antes de cada nível que define
+// PreAlertLead.
+func checkPreAlerts(state *AppState, usage time.Duration) {
+	for _, level := range config.AppConfig.AlertLevels {
+		if !level.Enabled || level.PreAlertLead <= 0 || state.warnedThresholds[level.Level] || state.preWarned[level.Level] {
+			continue
+		}
+		threshold := levelThreshold(state, level)
+		if usage < threshold-level.PreAlertLead || usage >= threshold {
+			continue
+		}
+		remaining := (threshold - usage).Round(time.Second)
+		log.Printf("Pré-alerta: nível %s em %v.", level.Level, remaining)
+		go notifications.ShowQuietNotification("Focus Helper", fmt.Sprintf("Torre: alerta %s em %s.", level.Level, formatMinutes(remaining)))
+		state.preWarned[level.Level] = true
+	}
+}
+
+// writeStatus grava o status consultado pelo comando `status` e por barras de status.
+func writeStatus(state *AppState, idle bool) {
+	now := time.Now()
+	st := status.Status{
+		UpdatedAt: now,
+		Mode:      status.ModeHyperfocus,
+		Idle:      idle,
+		Task:      flightPlanTask(state),
+	}
+	if until := alerts.snoozedUntilTime(); until.After(now) {
+		st.SnoozedUntil = &until
+	}
+	if !idle {
+		st.SessionSeconds = int64(now.Sub(state.continuousUsageStartTime).Seconds())
+	}
+	if state.currentHyperfocusState != nil {
+		st.Level = state.currentHyperfocusState.Level
+	}
+	if state.budget != nil {
+		st.DailySeconds = int64(state.budget.active.Seconds())
+	}
+	if level, threshold, ok := nextAlertLevel(state); ok {
+		st.NextLevel = level.Level
+		at := state.continuousUsageStartTime.Add(threshold)
+		st.NextLevelAt = &at
+	}
+	if err := status.Write(config.AppConfig.StatusFile, st); err != nil {
+		log.Printf("Erro ao gravar arquivo de status: %v", err)
+	}
+	stateReport.report(st)
+}
+
+// runStatusCommand exibe o status do daemon e a contagem regressiva para o próximo alerta.
+func runStatusCommand(args []string) {
+	fs := flag.NewFlagSet("status", flag.ExitOnError)
+	debugFlag := fs.Bool("debug", false, "Use the debug configuration")
+	jsonFlag := fs.Bool("json", false, "Print the raw status as JSON")
+	fs.Parse(args)
+
+	config.Init(*debugFlag)
+	st, err := status.Read(config.AppConfig.StatusFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Focus Helper não está rodando ou ainda não gravou o status: %v\n", err)
+		os.Exit(1)
+	}
+	if *jsonFlag {
+		out, _ := json.MarshalIndent(st, "", "  ")
+		fmt.Println(string(out))
+		return
+	}
+	if stale := time.Since(st.UpdatedAt); stale > 3*config.AppConfig.ActivityCheckRate {
+		fmt.Printf("(status desatualizado há %v) ", stale.Round(time.Second))
+	}
+	fmt.Println(st.Summary(time.Now()))
+}
@@ -15,7 +15,13 @@ func Init(filepath string) (*sql.DB, error) {
 		return nil, err
 	}
 
-	createTablesSQL := `CREATE TABLE IF NOT EXISTS wellbeing_checks (id INTEGER PRIMARY KEY, timestamp DATETIME, question TEXT, answer TEXT);`
+	createTablesSQL := `CREATE TABLE IF NOT EXISTS wellbeing_checks (id INTEGER PRIMARY KEY, timestamp DATETIME, question TEXT, answer TEXT);
+	CREATE TABLE IF NOT EXISTS daily_usage (day TEXT PRIMARY KEY, active_seconds INTEGER);
+	CREATE TABLE IF NOT EXISTS pomodoro_cycles (id INTEGER PRIMARY KEY, started_at DATETIME, completed_at DATETIME, work_seconds INTEGER, break_seconds INTEGER, long_break BOOLEAN, break_violations INTEGER);
+	CREATE TABLE IF NOT EXISTS alerts (id INTEGER PRIMARY KEY, timestamp DATETIME, level TEXT, session_seconds INTEGER);
+	CREATE TABLE IF NOT EXISTS alert_actions (id INTEGER PRIMARY KEY, alert_id INTEGER, timestamp DATETIME, action_type TEXT, exit_code INTEGER, output TEXT, error TEXT);
+	CREATE TABLE IF NOT EXISTS flight_plans (id INTEGER PRIMARY KEY, filed_at DATETIME, task TEXT, intention TEXT, planned_seconds INTEGER, closed_at DATETIME);
+	CREATE TABLE IF NOT EXISTS sessions (id INTEGER PRIMARY KEY, started_at DATETIME, ended_at DATETIME, focus_seconds INTEGER, level TEXT, task TEXT);`
 	_, err = db.Exec(createTablesSQL)
 	if err != nil {
 		return nil, err
@@ -39,3 +45,227 @@ func LogWellbeingCheck(db *sql.DB, question, answer string) {
 		log.Printf("Erro ao inserir log de bem-estar: %v", err)
 	}
 }
+
+// GetLastWellbeingAnswer retorna a resposta mais recente às perguntas de bem-estar.
+func GetLastWellbeingAnswer(db *sql.DB) (string, error) {
+	var answer string
+	err := db.QueryRow("SELECT answer FROM wellbeing_checks ORDER BY timestamp DESC LIMIT 1").Scan(&answer)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return answer, err
+}
+
+// WellbeingCheck é uma resposta às perguntas de bem-estar.
+type WellbeingCheck struct {
+	Timestamp time.Time
+	Question  string
+	Answer    string
+}
+
+// GetWellbeingChecks retorna as respostas de bem-estar registradas em [from, to).
+func GetWellbeingChecks(db *sql.DB, from, to time.Time) ([]WellbeingCheck, error) {
+	rows, err := db.Query("SELECT timestamp, question, answer FROM wellbeing_checks WHERE timestamp >= ? AND timestamp < ? ORDER BY timestamp", from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var checks []WellbeingCheck
+	for rows.Next() {
+		var check WellbeingCheck
+		if err := rows.Scan(&check.Timestamp, &check.Question, &check.Answer); err != nil {
+			return nil, err
+		}
+		checks = append(checks, check)
+	}
+	return checks, rows.Err()
+}
+
+// GetDailyUsage retorna o tempo ativo acumulado em um dia (formato AAAA-MM-DD).
+func GetDailyUsage(db *sql.DB, day string) (time.Duration, error) {
+	var seconds int64
+	err := db.QueryRow("SELECT active_seconds FROM daily_usage WHERE day = ?", day).Scan(&seconds)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
+
+// SaveDailyUsage grava o tempo ativo acumulado em um dia.
+func SaveDailyUsage(db *sql.DB, day string, active time.Duration) {
+	_, err := db.Exec("INSERT INTO daily_usage(day, active_seconds) VALUES(?, ?) ON CONFLICT(day) DO UPDATE SET active_seconds = excluded.active_seconds", day, int64(active.Seconds()))
+	if err != nil {
+		log.Printf("Erro ao salvar uso diário: %v", err)
+	}
+}
+
+// PomodoroCycle representa um ciclo de trabalho e pausa concluído.
+type PomodoroCycle struct {
+	StartedAt       time.Time
+	CompletedAt     time.Time
+	Work            time.Duration
+	Break           time.Duration
+	LongBreak       bool
+	BreakViolations int
+}
+
+// LogPomodoroCycle salva um ciclo pomodoro concluído.
+func LogPomodoroCycle(db *sql.DB, cycle PomodoroCycle) {
+	_, err := db.Exec("INSERT INTO pomodoro_cycles(started_at, completed_at, work_seconds, break_seconds, long_break, break_violations) VALUES(?, ?, ?, ?, ?, ?)",
+		cycle.StartedAt, cycle.CompletedAt, int64(cycle.Work.Seconds()), int64(cycle.Break.Seconds()), cycle.LongBreak, cycle.BreakViolations)
+	if err != nil {
+		log.Printf("Erro ao inserir ciclo pomodoro: %v", err)
+	}
+}
+
+// FlightPlan é uma intenção de foco declarada pelo usuário antes de um bloco de trabalho.
+type FlightPlan struct {
+	ID        int64
+	FiledAt   time.Time
+	Task      string
+	Intention string
+	Planned   time.Duration
+}
+
+// FileFlightPlan registra um novo plano de voo, encerrando o anterior.
+func FileFlightPlan(db *sql.DB, plan FlightPlan) (int64, error) {
+	if err := CloseFlightPlan(db); err != nil {
+		return 0, err
+	}
+	result, err := db.Exec("INSERT INTO flight_plans(filed_at, task, intention, planned_seconds) VALUES(?, ?, ?, ?)",
+		plan.FiledAt, plan.Task, plan.Intention, int64(plan.Planned.Seconds()))
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+// CloseFlightPlan encerra o plano de voo ativo, se houver.
+func CloseFlightPlan(db *sql.DB) error {
+	_, err := db.Exec("UPDATE flight_plans SET closed_at = ? WHERE closed_at IS NULL", time.Now())
+	return err
+}
+
+// GetActiveFlightPlan retorna o plano de voo ativo ou nil.
+func GetActiveFlightPlan(db *sql.DB) (*FlightPlan, error) {
+	var plan FlightPlan
+	var seconds int64
+	err := db.QueryRow("SELECT id, filed_at, task, intention, planned_seconds FROM flight_plans WHERE closed_at IS NULL ORDER BY id DESC LIMIT 1").
+		Scan(&plan.ID, &plan.FiledAt, &plan.Task, &plan.Intention, &seconds)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	plan.Planned = time.Duration(seconds) * time.Second
+	return &plan, nil
+}
+
+// LogAlert registra o disparo de um nível de alerta e retorna seu ID.
+func LogAlert(db *sql.DB, level string, session time.Duration) int64 {
+	result, err := db.Exec("INSERT INTO alerts(timestamp, level, session_seconds) VALUES(?, ?, ?)", time.Now(), level, int64(session.Seconds()))
+	if err != nil {
+		log.Printf("Erro ao inserir alerta: %v", err)
+		return 0
+	}
+	id, _ := result.LastInsertId()
+	return id
+}
+
+// GetLastAlertTime retorna o horário do último disparo do nível, ou zero.
+func GetLastAlertTime(db *sql.DB, level string) (time.Time, error) {
+	var last time.Time
+	err := db.QueryRow("SELECT timestamp FROM alerts WHERE level = ? ORDER BY timestamp DESC LIMIT 1", level).Scan(&last)
+	if err == sql.ErrNoRows {
+		return time.Time{}, nil
+	}
+	return last, err
+}
+
+// AlertActionRecord é o resultado de uma ação executada em um alerta.
+type AlertActionRecord struct {
+	AlertID    int64
+	ActionType string
+	ExitCode   int
+	Output     string
+	Error      string
+}
+
+// LogAlertAction salva o resultado de uma ação no histórico do alerta.
+func LogAlertAction(db *sql.DB, record AlertActionRecord) {
+	_, err := db.Exec("INSERT INTO alert_actions(alert_id, timestamp, action_type, exit_code, output, error) VALUES(?, ?, ?, ?, ?, ?)",
+		record.AlertID, time.Now(), record.ActionType, record.ExitCode, record.Output, record.Error)
+	if err != nil {
+		log.Printf("Erro ao inserir resultado de ação: %v", err)
+	}
+}
+
+// AlertRecord é um disparo de nível de alerta registrado no histórico.
+type AlertRecord struct {
+	Timestamp time.Time
+	Level     string
+	Session   time.Duration
+}
+
+// GetAlerts retorna os alertas disparados em [from, to).
+func GetAlerts(db *sql.DB, from, to time.Time) ([]AlertRecord, error) {
+	rows, err := db.Query("SELECT timestamp, level, session_seconds FROM alerts WHERE timestamp >= ? AND timestamp < ? ORDER BY timestamp", from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var alerts []AlertRecord
+	for rows.Next() {
+		var alert AlertRecord
+		var seconds int64
+		if err := rows.Scan(&alert.Timestamp, &alert.Level, &seconds); err != nil {
+			return nil, err
+		}
+		alert.Session = time.Duration(seconds) * time.Second
+		alerts = append(alerts, alert)
+	}
+	return alerts, rows.Err()
+}
+
+// Session é uma sessão de foco encerrada por uma pausa suficiente ou pelo
+// encerramento do Focus Helper.
+type Session struct {
+	StartedAt time.Time
+	EndedAt   time.Time
+	Focus     time.Duration // uso acumulado, descontadas as pausas insuficientes
+	Level     string        // nível de hiperfoco mais alto atingido
+	Task      string
+}
+
+// LogSession salva uma sessão de foco encerrada.
+func LogSession(db *sql.DB, session Session) {
+	_, err := db.Exec("INSERT INTO sessions(started_at, ended_at, focus_seconds, level, task) VALUES(?, ?, ?, ?, ?)",
+		session.StartedAt, session.EndedAt, int64(session.Focus.Seconds()), session.Level, session.Task)
+	if err != nil {
+		log.Printf("Erro ao inserir sessão: %v", err)
+	}
+}
+
+// GetSessions retorna as sessões encerradas em [from, to).
+func GetSessions(db *sql.DB, from, to time.Time) ([]Session, error) {
+	rows, err := db.Query("SELECT started_at, ended_at, focus_seconds, level, task FROM sessions WHERE ended_at >= ? AND ended_at < ? ORDER BY started_at", from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var sessions []Session
+	for rows.Next() {
+		var session Session
+		var seconds int64
+		if err := rows.Scan(&session.StartedAt, &session.EndedAt, &seconds, &session.Level, &session.Task); err != nil {
+			return nil, err
+		}
+		session.Focus = time.Duration(seconds) * time.Second
+		sessions = append(sessions, session)
+	}
+	return sessions, rows.Err()
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"text/template"
+	"time"
+
+	"github.com/brutalzinn/focus-helper/actions"
+	"github.com/brutalzinn/focus-helper/config"
+	"github.com/brutalzinn/focus-helper/database"
+	"github.com/brutalzinn/focus-helper/integrations"
+)
+
+const (
+	defaultDigestSubject = "Focus Helper: resumo de {{.Day}}"
+	defaultDigestBody    = `Resumo do Focus Helper de {{.Day}}
+
+Tempo de foco: {{duration .TotalFocus}} em {{len .Sessions}} sessões
+{{- if .Sessions}}
+Sessão mais longa: {{duration .LongestSession}}
+
+Sessões:
+{{- range .Sessions}}
+  {{clock .StartedAt}}-{{clock .EndedAt}}  {{duration .Focus}}{{if .Level}}  nível {{.Level}}{{end}}{{if .Task}}  ({{.Task}}){{end}}
+{{- end}}
+{{- end}}
+{{if .Alerts}}
+Alertas:
+{{- range .Alerts}}
+  {{.Level}}: {{.Count}}
+{{- end}}
+{{end}}
+{{- if .Wellbeing}}
+Bem-estar:
+{{- range .Wellbeing}}
+  {{clock .Timestamp}}  {{.Question}} {{.Answer}}
+{{- end}}
+{{end}}`
+)
+
+// digestData são os dados expostos ao template do resumo diário.
+type digestData struct {
+	Day            string // data do início do período resumido
+	Sessions       []database.Session
+	TotalFocus     time.Duration
+	LongestSession time.Duration
+	Alerts         []alertCount
+	Wellbeing      []database.WellbeingCheck
+}
+
+type alertCount struct {
+	Level string
+	Count int
+}
+
+var digestFuncs = template.FuncMap{
+	"duration": formatMinutes,
+	"clock":    func(t time.Time) string { return t.Format("15:04") },
+}
+
+// digestLoop envia o resumo diário por e-mail no horário configurado.
+func digestLoop(ctx context.Context) {
+	cfg := config.AppConfig.DailyDigest
+	for {
+		next := nextDigestTime(time.Now(), cfg.SendAt)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Until(next)):
+		}
+		if err := sendDailyDigest(ctx, cfg, next); err != nil {
+			log.Printf("Erro ao enviar resumo diário: %v", err)
+		}
+	}
+}
+
+// nextDigestTime retorna o próximo horário de envio depois de now.
+func nextDigestTime(now time.Time, sendAt time.Duration) time.Time {
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	next := midnight.Add(sendAt)
+	if !next.After(now) {
+		next = midnight.AddDate(0, 0, 1).Add(sendAt)
+	}
+	return next
+}
+
+// sendDailyDigest envia o resumo das 24 horas anteriores a until.
+func sendDailyDigest(ctx context.Context, cfg config.DailyDigestConfig, until time.Time) error {
+	data, err := loadDigest(until.AddDate(0, 0, -1), until)
+	if err != nil {
+		return err
+	}
+	subject, err := renderDigest("digest_subject", cfg.Subject, defaultDigestSubject, data)
+	if err != nil {
+		return err
+	}
+	body, err := renderDigest("digest_body", cfg.Body, defaultDigestBody, data)
+	if err != nil {
+		return err
+	}
+	sendCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+	if err := actions.SendEmail(sendCtx, integrations.Email{To: cfg.To, Subject: subject, Body: body}); err != nil {
+		return err
+	}
+	log.Printf("Resumo diário de %s enviado para %v.", data.Day, cfg.To)
+	return nil
+}
+
+func loadDigest(from, to time.Time) (digestData, error) {
+	data := digestData{Day: from.Format("02/01/2006")}
+	var err error
+	if data.Sessions, err = database.GetSessions(db, from, to); err != nil {
+		return data, fmt.Errorf("erro ao consultar sessões: %w", err)
+	}
+	for _, session := range data.Sessions {
+		data.TotalFocus += session.Focus
+		data.LongestSession = max(data.LongestSession, session.Focus)
+	}
+	alerts, err := database.GetAlerts(db, from, to)
+	if err != nil {
+		return data, fmt.Errorf("erro ao consultar alertas: %w", err)
+	}
+	counts := make(map[string]int)
+	for _, alert := range alerts {
+		counts[alert.Level]++
+	}
+	for level, count := range counts {
+		data.Alerts = append(data.Alerts, alertCount{Level: level, Count: count})
+	}
+	sort.Slice(data.Alerts, func(i, j int) bool { return data.Alerts[i].Level < data.Alerts[j].Level })
+	if data.Wellbeing, err = database.GetWellbeingChecks(db, from, to); err != nil {
+		return data, fmt.Errorf("erro ao consultar respostas de bem-estar: %w", err)
+	}
+	return data, nil
+}
+
+func renderDigest(name, text, fallback string, data digestData) (string, error) {
+	if text == "" {
+		text = fallback
+	}
+	tmpl, err := template.New(name).Funcs(digestFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("erro no template %s: %w", name, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("erro ao renderizar %s: %w", name, err)
+	}
+	return buf.String(), nil
+}
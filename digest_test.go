@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brutalzinn/focus-helper/config"
+	"github.com/brutalzinn/focus-helper/database"
+	"github.com/brutalzinn/focus-helper/integrations/smtptest"
+)
+
+// openTestDB aponta o banco global para um arquivo temporário.
+func openTestDB(t *testing.T) {
+	t.Helper()
+	var err error
+	config.AppConfig.DatabaseFile = filepath.Join(t.TempDir(), "focus.db")
+	db, err = database.Init(config.AppConfig.DatabaseFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+}
+
+func TestSendDailyDigest(t *testing.T) {
+	openTestDB(t)
+	srv := smtptest.NewServer(t)
+	config.AppConfig.SMTP = config.SMTPConfig{Host: srv.Host, Port: srv.Port, From: "Focus Helper <focus@exemplo.com>"}
+
+	until := time.Now().Add(time.Hour).Truncate(time.Minute)
+	from := until.AddDate(0, 0, -1)
+	start := until.Add(-5 * time.Hour)
+	database.LogSession(db, database.Session{StartedAt: start, EndedAt: start.Add(90 * time.Minute), Focus: 80 * time.Minute, Level: "HYPERFOCUS_HIGH", Task: "relatório"})
+	database.LogSession(db, database.Session{StartedAt: start.Add(2 * time.Hour), EndedAt: start.Add(150 * time.Minute), Focus: 30 * time.Minute})
+	// Fora do período: terminou antes do início do resumo.
+	database.LogSession(db, database.Session{StartedAt: from.Add(-2 * time.Hour), EndedAt: from.Add(-time.Hour), Focus: time.Hour})
+	database.LogAlert(db, "HYPERFOCUS_HIGH", 80*time.Minute)
+	database.LogAlert(db, "HYPERFOCUS_HIGH", 85*time.Minute)
+	database.LogAlert(db, "HYPERFOCUS_LOW", 40*time.Minute)
+	database.LogWellbeingCheck(db, "Como você está?", "Sim")
+
+	cfg := config.DailyDigestConfig{Enabled: true, To: []string{"piloto@exemplo.com"}}
+	if err := sendDailyDigest(context.Background(), cfg, until); err != nil {
+		t.Fatal(err)
+	}
+	msg := srv.Next(t)
+	parsed, err := msg.Parse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	subject, _ := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	if want := "Focus Helper: resumo de " + from.Format("02/01/2006"); subject != want {
+		t.Errorf("assunto %q, esperado %q", subject, want)
+	}
+	raw, _ := io.ReadAll(quotedprintable.NewReader(parsed.Body))
+	body := string(raw)
+	for _, want := range []string{
+		"Resumo do Focus Helper de " + from.Format("02/01/2006"),
+		"Tempo de foco: 110 minutos em 2 sessões",
+		"Sessão mais longa: 80 minutos",
+		start.Format("15:04") + "-" + start.Add(90*time.Minute).Format("15:04") + "  80 minutos  nível HYPERFOCUS_HIGH  (relatório)",
+		"HYPERFOCUS_HIGH: 2",
+		"HYPERFOCUS_LOW: 1",
+		"Como você está? Sim",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("corpo sem %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestNextDigestTime(t *testing.T) {
+	loc := time.Local
+	tests := []struct {
+		now  time.Time
+		want time.Time
+	}{
+		{time.Date(2026, 3, 10, 8, 0, 0, 0, loc), time.Date(2026, 3, 10, 21, 0, 0, 0, loc)},
+		{time.Date(2026, 3, 10, 21, 0, 0, 0, loc), time.Date(2026, 3, 11, 21, 0, 0, 0, loc)},
+		{time.Date(2026, 3, 31, 23, 0, 0, 0, loc), time.Date(2026, 4, 1, 21, 0, 0, 0, loc)},
+	}
+	for _, tt := range tests {
+		if got := nextDigestTime(tt.now, 21*time.Hour); !got.Equal(tt.want) {
+			t.Errorf("nextDigestTime(%v) = %v, esperado %v", tt.now, got, tt.want)
+		}
+	}
+}
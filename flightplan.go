@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/brutalzinn/focus-helper/config"
+	"github.com/brutalzinn/focus-helper/database"
+)
+
+// runFlightPlanCommand registra (ou encerra) um plano de voo. O daemon relê o
+// plano ativo quando o arquivo do banco muda.
+func runFlightPlanCommand(args []string) {
+	fs := flag.NewFlagSet("flight-plan", flag.ExitOnError)
+	debugFlag := fs.Bool("debug", false, "Use the debug configuration")
+	task := fs.String("task", "", "Task you are about to focus on, e.g. \"coding the parser\"")
+	intention := fs.String("intention", "", "What you intend to achieve in this block")
+	duration := fs.Duration("duration", 0, "Planned duration of the focus block, e.g. 2h")
+	closePlan := fs.Bool("close", false, "Close the active flight plan")
+	fs.Parse(args)
+
+	config.Init(*debugFlag)
+	planDB, err := database.Init(config.AppConfig.DatabaseFile)
+	if err != nil {
+		log.Fatalf("Falha ao inicializar banco de dados: %v", err)
+	}
+	defer planDB.Close()
+
+	if *closePlan {
+		if err := database.CloseFlightPlan(planDB); err != nil {
+			log.Fatalf("Erro ao encerrar plano de voo: %v", err)
+		}
+		fmt.Println("Plano de voo encerrado.")
+		return
+	}
+	if *task == "" || *duration <= 0 {
+		fmt.Fprintln(os.Stderr, "uso: focus-helper flight-plan --task \"tarefa\" --duration 2h [--intention \"objetivo\"]")
+		os.Exit(2)
+	}
+	id, err := database.FileFlightPlan(planDB, database.FlightPlan{
+		FiledAt:   time.Now(),
+		Task:      *task,
+		Intention: *intention,
+		Planned:   *duration,
+	})
+	if err != nil {
+		log.Fatalf("Erro ao registrar plano de voo: %v", err)
+	}
+	fmt.Printf("Plano de voo %d registrado: %q por %v.\n", id, *task, *duration)
+}
+
+// flightPlanCache evita consultar o banco a cada verificação de atividade. O
+// comando flight-plan roda em outro processo, então a mudança é percebida pelo
+// contador de alterações do cabeçalho do SQLite, incrementado a cada transação.
+type flightPlanCache struct {
+	loaded  bool
+	counter uint32
+}
+
+// changed indica se o banco mudou desde a última leitura do plano.
+func (c *flightPlanCache) changed(path string) bool {
+	counter, err := sqliteChangeCounter(path)
+	if err != nil {
+		return true
+	}
+	if c.loaded && counter == c.counter {
+		return false
+	}
+	c.loaded, c.counter = true, counter
+	return true
+}
+
+// sqliteChangeCounter lê o "file change counter" do cabeçalho do banco.
+func sqliteChangeCounter(path string) (uint32, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	var header [4]byte
+	if _, err := f.ReadAt(header[:], 24); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint32(header[:]), nil
+}
+
+// refreshFlightPlan carrega o plano de voo ativo quando o banco muda. Um plano
+// novo reinicia os alertas da sessão, já que os limites passam a ser relativos
+// a ele.
+func refreshFlightPlan(state *AppState) {
+	if !state.planCache.changed(config.AppConfig.DatabaseFile) {
+		return
+	}
+	plan, err := database.GetActiveFlightPlan(db)
+	if err != nil {
+		log.Printf("Erro ao carregar plano de voo: %v", err)
+		state.planCache.loaded = false
+		return
+	}
+	if plan != nil && (state.flightPlan == nil || state.flightPlan.ID != plan.ID) {
+		log.Printf("Plano de voo ativo: %q por %v.", plan.Task, plan.Planned)
+		state.warnedThresholds = make(map[string]bool)
+		state.preWarned = make(map[string]bool)
+		state.currentHyperfocusState = nil
+		// Um plano registrado antes do uso atual começa junto com ele.
+		state.planStart = plan.FiledAt
+		if state.planStart.Before(state.continuousUsageStartTime) {
+			state.planStart = state.continuousUsageStartTime
+		}
+	}
+	state.flightPlan = plan
+}
+
+// levelThreshold retorna o limite de uso de um nível. Com um plano de voo
+// ativo, o limite é o fim planejado somado ao PlanOffset do nível, contado a
+// partir do início do plano; o desconto de uma pausa insuficiente não o adia.
+func levelThreshold(state *AppState, level config.AlertLevel) time.Duration {
+	if state.flightPlan == nil {
+		return level.Threshold
+	}
+	return state.planStart.Add(state.flightPlan.Planned + level.PlanOffset).Sub(state.continuousUsageStartTime)
+}
+
+func flightPlanTask(state *AppState) string {
+	if state.flightPlan == nil {
+		return ""
+	}
+	return state.flightPlan.Task
+}
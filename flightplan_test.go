@@ -0,0 +1,88 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/brutalzinn/focus-helper/activity"
+	"github.com/brutalzinn/focus-helper/config"
+	"github.com/brutalzinn/focus-helper/database"
+)
+
+func TestRefreshFlightPlanReloadsOnChange(t *testing.T) {
+	openTestDB(t)
+	now := time.Now()
+	state := &AppState{
+		continuousUsageStartTime: now.Add(-30 * time.Minute),
+		warnedThresholds:         map[string]bool{"LOW": true},
+		preWarned:                make(map[string]bool),
+	}
+	refreshFlightPlan(state)
+	if state.flightPlan != nil {
+		t.Fatalf("plano %+v sem nenhum registrado", state.flightPlan)
+	}
+
+	// Sem mudança no banco, o plano em memória não é consultado de novo.
+	cached := &database.FlightPlan{ID: 99, Task: "em memória"}
+	state.flightPlan = cached
+	refreshFlightPlan(state)
+	if state.flightPlan != cached {
+		t.Fatal("o plano foi recarregado sem mudança no banco")
+	}
+	state.flightPlan = nil
+
+	// Um plano registrado antes da sessão começa junto com o uso atual.
+	if _, err := database.FileFlightPlan(db, database.FlightPlan{FiledAt: now.Add(-time.Hour), Task: "relatório", Planned: time.Hour}); err != nil {
+		t.Fatal(err)
+	}
+	refreshFlightPlan(state)
+	if state.flightPlan == nil || state.flightPlan.Task != "relatório" {
+		t.Fatalf("plano carregado = %+v", state.flightPlan)
+	}
+	if !state.planStart.Equal(state.continuousUsageStartTime) {
+		t.Errorf("início do plano %v, esperado o início do uso %v", state.planStart, state.continuousUsageStartTime)
+	}
+	if len(state.warnedThresholds) != 0 {
+		t.Errorf("alertas não reiniciados pelo plano novo: %v", state.warnedThresholds)
+	}
+
+	if err := database.CloseFlightPlan(db); err != nil {
+		t.Fatal(err)
+	}
+	refreshFlightPlan(state)
+	if state.flightPlan != nil {
+		t.Errorf("plano encerrado continua ativo: %+v", state.flightPlan)
+	}
+}
+
+func TestCoolDownKeepsPlanThresholds(t *testing.T) {
+	config.AppConfig.AlertLevels = []config.AlertLevel{
+		{Enabled: true, Level: "MEDIUM", Threshold: 90 * time.Minute},
+		{Enabled: true, Level: "HIGH", Threshold: 150 * time.Minute, PlanOffset: 30 * time.Minute},
+	}
+	now := time.Now()
+	// Uso desde 130 minutos atrás, plano de 60 minutos registrado há 70: o
+	// limite do MEDIUM já passou e o do HIGH vence daqui a 20 minutos.
+	state := &AppState{
+		continuousUsageStartTime: now.Add(-130 * time.Minute),
+		lastActivityTime:         now,
+		flightPlan:               &database.FlightPlan{Task: "relatório", Planned: time.Hour},
+		planStart:                now.Add(-70 * time.Minute),
+		warnedThresholds:         map[string]bool{"MEDIUM": true},
+		preWarned:                map[string]bool{"MEDIUM": true},
+		currentHyperfocusState:   &config.HyperfocusState{Level: "MEDIUM"},
+	}
+	highAt := state.planStart.Add(90 * time.Minute)
+
+	coolDownState(state, activity.BreakResult{Session: 130 * time.Minute, Taken: time.Minute, Required: 10 * time.Minute, Remaining: 20 * time.Minute})
+
+	if !state.warnedThresholds["MEDIUM"] {
+		t.Error("MEDIUM liberado apesar de o fim do plano já ter passado")
+	}
+	if state.currentHyperfocusState == nil {
+		t.Error("nível atual descartado com alertas ainda disparados")
+	}
+	if got := state.continuousUsageStartTime.Add(levelThreshold(state, config.AppConfig.AlertLevels[1])); !got.Equal(highAt) {
+		t.Errorf("HIGH dispara em %v, esperado %v", got, highAt)
+	}
+}
@@ -0,0 +1,138 @@
+package integrations
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTPServer identifica o servidor usado para enviar e-mails.
+type SMTPServer struct {
+	Host     string
+	Port     int
+	Username string // vazio envia sem autenticação
+	Password string
+	From     string
+	StartTLS bool // exige STARTTLS antes da autenticação
+}
+
+// Email é uma mensagem de texto simples.
+type Email struct {
+	To      []string
+	Subject string
+	Body    string
+}
+
+// SendEmail envia o e-mail pelo servidor SMTP, respeitando o prazo do contexto.
+func SendEmail(ctx context.Context, server SMTPServer, email Email) error {
+	if server.Host == "" {
+		return fmt.Errorf("servidor SMTP não configurado")
+	}
+	if len(email.To) == 0 {
+		return fmt.Errorf("e-mail sem destinatários")
+	}
+	message, err := buildEmail(server.From, email)
+	if err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(server.Host, strconv.Itoa(server.Port))
+	dialer := net.Dialer{Timeout: 10 * time.Second}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("erro ao conectar ao servidor SMTP %s: %w", addr, err)
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(time.Minute)
+	}
+	conn.SetDeadline(deadline)
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	client, err := smtp.NewClient(conn, server.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("erro ao iniciar sessão SMTP: %w", err)
+	}
+	defer client.Close()
+
+	if server.StartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("servidor SMTP %s não suporta STARTTLS", addr)
+		}
+		if err := client.StartTLS(&tls.Config{ServerName: server.Host}); err != nil {
+			return fmt.Errorf("erro no STARTTLS: %w", err)
+		}
+	}
+	if server.Username != "" {
+		// PlainAuth só envia a senha sobre TLS ou para localhost.
+		if err := client.Auth(smtp.PlainAuth("", server.Username, server.Password, server.Host)); err != nil {
+			return fmt.Errorf("erro na autenticação SMTP: %w", err)
+		}
+	}
+	if err := client.Mail(addressOnly(server.From)); err != nil {
+		return fmt.Errorf("MAIL FROM: %w", err)
+	}
+	for _, to := range email.To {
+		if err := client.Rcpt(addressOnly(to)); err != nil {
+			return fmt.Errorf("RCPT TO %s: %w", to, err)
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("DATA: %w", err)
+	}
+	if _, err := w.Write(message); err != nil {
+		return fmt.Errorf("erro ao enviar mensagem: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("erro ao enviar mensagem: %w", err)
+	}
+	return client.Quit()
+}
+
+func buildEmail(from string, email Email) ([]byte, error) {
+	var buf bytes.Buffer
+	headers := [][2]string{
+		{"From", from},
+		{"To", strings.Join(email.To, ", ")},
+		{"Subject", mime.QEncoding.Encode("utf-8", email.Subject)},
+		{"Date", time.Now().Format(time.RFC1123Z)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "text/plain; charset=UTF-8"},
+		{"Content-Transfer-Encoding", "quoted-printable"},
+	}
+	for _, header := range headers {
+		if strings.ContainsAny(header[1], "\r\n") {
+			return nil, fmt.Errorf("cabeçalho %s inválido", header[0])
+		}
+		fmt.Fprintf(&buf, "%s: %s\r\n", header[0], header[1])
+	}
+	buf.WriteString("\r\n")
+	qp := quotedprintable.NewWriter(&buf)
+	if _, err := qp.Write([]byte(strings.ReplaceAll(strings.ReplaceAll(email.Body, "\r\n", "\n"), "\n", "\r\n"))); err != nil {
+		return nil, err
+	}
+	if err := qp.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// addressOnly extrai o endereço de "Nome <endereço>".
+func addressOnly(address string) string {
+	if parsed, err := mail.ParseAddress(address); err == nil {
+		return parsed.Address
+	}
+	return address
+}
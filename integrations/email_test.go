@@ -0,0 +1,158 @@
+package integrations
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/brutalzinn/focus-helper/integrations/smtptest"
+)
+
+func TestSendEmail(t *testing.T) {
+	tests := []struct {
+		name         string
+		username     string
+		email        Email
+		wantBody     string
+		wantUsername string
+	}{
+		{
+			name: "sem autenticação",
+			email: Email{
+				To:      []string{"Piloto <piloto@exemplo.com>", "torre@exemplo.com"},
+				Subject: "Focus Helper: alerta HIGH às 15h",
+				Body:    "Faça uma pausa.\nSessão: 95 minutos\n",
+			},
+			wantBody: "Faça uma pausa.\nSessão: 95 minutos\n",
+		},
+		{
+			name:     "com autenticação e linha longa",
+			username: "focus",
+			email: Email{
+				To:      []string{"piloto@exemplo.com"},
+				Subject: "resumo",
+				Body:    strings.Repeat("plano de voo ", 10) + "= fim",
+			},
+			wantBody:     strings.Repeat("plano de voo ", 10) + "= fim\n",
+			wantUsername: "focus",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := smtptest.NewServer(t)
+			server := SMTPServer{Host: srv.Host, Port: srv.Port, Username: tt.username, Password: "segredo", From: "Focus Helper <focus@exemplo.com>"}
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := SendEmail(ctx, server, tt.email); err != nil {
+				t.Fatal(err)
+			}
+
+			msg := srv.Next(t)
+			if msg.From != "focus@exemplo.com" {
+				t.Errorf("MAIL FROM %q", msg.From)
+			}
+			if want := []string{"piloto@exemplo.com", "torre@exemplo.com"}[:len(tt.email.To)]; !slices.Equal(msg.To, want) {
+				t.Errorf("RCPT TO %v, esperado %v", msg.To, want)
+			}
+			if msg.Username != tt.wantUsername || (tt.wantUsername != "" && msg.Password != "segredo") {
+				t.Errorf("AUTH com %q/%q", msg.Username, msg.Password)
+			}
+
+			parsed, err := msg.Parse()
+			if err != nil {
+				t.Fatal(err)
+			}
+			subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+			if err != nil || subject != tt.email.Subject {
+				t.Errorf("Subject %q (%v), esperado %q", subject, err, tt.email.Subject)
+			}
+			headers := map[string]string{
+				"From":                      server.From,
+				"To":                        strings.Join(tt.email.To, ", "),
+				"MIME-Version":              "1.0",
+				"Content-Type":              "text/plain; charset=UTF-8",
+				"Content-Transfer-Encoding": "quoted-printable",
+			}
+			for key, want := range headers {
+				if got := parsed.Header.Get(key); got != want {
+					t.Errorf("%s: %q, esperado %q", key, got, want)
+				}
+			}
+			if date, err := parsed.Header.Date(); err != nil || time.Since(date) > time.Minute {
+				t.Errorf("Date %v (%v)", date, err)
+			}
+			// O servidor entrega as linhas com \n, como o textproto.DotReader.
+			for _, line := range strings.Split(string(msg.Data), "\n") {
+				if len(line) > 78 {
+					t.Errorf("linha com %d caracteres no corpo codificado", len(line))
+				}
+			}
+			body, err := io.ReadAll(quotedprintable.NewReader(parsed.Body))
+			if err != nil || string(body) != tt.wantBody {
+				t.Errorf("corpo %q (%v), esperado %q", body, err, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestSendEmailErrors(t *testing.T) {
+	srv := smtptest.NewServer(t)
+	ctx := context.Background()
+	email := Email{To: []string{"piloto@exemplo.com"}, Subject: "s", Body: "b"}
+	tests := []struct {
+		name   string
+		server SMTPServer
+		email  Email
+	}{
+		{"sem servidor", SMTPServer{}, email},
+		{"sem destinatários", SMTPServer{Host: srv.Host, Port: srv.Port}, Email{Subject: "s"}},
+		{"cabeçalho com quebra de linha", SMTPServer{Host: srv.Host, Port: srv.Port}, Email{To: []string{"a@b.c\r\nBcc: x@y.z"}, Subject: "s"}},
+		{"STARTTLS exigido e não suportado", SMTPServer{Host: srv.Host, Port: srv.Port, StartTLS: true}, email},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := SendEmail(ctx, tt.server, tt.email); err == nil {
+				t.Error("esperado erro")
+			}
+		})
+	}
+	select {
+	case msg := <-srv.Messages:
+		t.Errorf("mensagem enviada apesar do erro: %+v", msg)
+	default:
+	}
+}
+
+// TestSendEmailDeadline usa um servidor que aceita a conexão e nunca envia a
+// saudação: o envio termina no prazo do contexto.
+func TestSendEmailDeadline(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	go func() {
+		conn, err := l.Accept()
+		if err == nil {
+			defer conn.Close()
+			io.Copy(io.Discard, conn)
+		}
+	}()
+	addr := l.Addr().(*net.TCPAddr)
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err = SendEmail(ctx, SMTPServer{Host: "127.0.0.1", Port: addr.Port}, Email{To: []string{"piloto@exemplo.com"}})
+	if err == nil {
+		t.Fatal("esperado erro de prazo")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("envio levou %v com prazo de 200ms", elapsed)
+	}
+}
@@ -3,14 +3,39 @@ package integrations
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	texttospeech "cloud.google.com/go/texttospeech/apiv1"
 	"cloud.google.com/go/texttospeech/apiv1/texttospeechpb"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 )
 
-func SynthesizeSpeech(text string) ([]byte, error) {
-	ctx := context.Background()
-	client, err := texttospeech.NewClient(ctx)
+// GoogleSpeech descreve uma síntese no Google Cloud Text-to-Speech.
+type GoogleSpeech struct {
+	Endpoint   string // vazio usa o endpoint padrão do Google
+	Insecure   bool   // conexão sem TLS e sem credenciais, para substitutos locais
+	Text       string
+	Voice      string  // ex.: pt-BR-Wavenet-B; o idioma vem do prefixo
+	Rate       float64 // 0.25 a 4.0; zero usa o padrão
+	Pitch      float64 // semitons, -20 a 20
+	SampleRate int
+}
+
+// SynthesizeSpeech retorna a fala em um wav PCM de 16 bits.
+func SynthesizeSpeech(ctx context.Context, speech GoogleSpeech) ([]byte, error) {
+	var opts []option.ClientOption
+	if speech.Endpoint != "" {
+		opts = append(opts, option.WithEndpoint(speech.Endpoint))
+	}
+	if speech.Insecure {
+		opts = append(opts,
+			option.WithoutAuthentication(),
+			option.WithGRPCDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())),
+		)
+	}
+	client, err := texttospeech.NewClient(ctx, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("texttospeech.NewClient: %w", err)
 	}
@@ -18,15 +43,17 @@ func SynthesizeSpeech(text string) ([]byte, error) {
 
 	req := texttospeechpb.SynthesizeSpeechRequest{
 		Input: &texttospeechpb.SynthesisInput{
-			InputSource: &texttospeechpb.SynthesisInput_Text{Text: text},
+			InputSource: &texttospeechpb.SynthesisInput_Text{Text: speech.Text},
 		},
 		Voice: &texttospeechpb.VoiceSelectionParams{
-			LanguageCode: "pt-BR",
-			Name:         "pt-BR-Wavenet-B",
-			SsmlGender:   texttospeechpb.SsmlVoiceGender_MALE,
+			LanguageCode: languageCode(speech.Voice),
+			Name:         speech.Voice,
 		},
 		AudioConfig: &texttospeechpb.AudioConfig{
-			AudioEncoding: texttospeechpb.AudioEncoding_MP3,
+			AudioEncoding:   texttospeechpb.AudioEncoding_LINEAR16,
+			SpeakingRate:    speech.Rate,
+			Pitch:           speech.Pitch,
+			SampleRateHertz: int32(speech.SampleRate),
 		},
 	}
 
@@ -37,3 +64,12 @@ func SynthesizeSpeech(text string) ([]byte, error) {
 
 	return resp.AudioContent, nil
 }
+
+// languageCode extrai o idioma do nome da voz: pt-BR-Wavenet-B -> pt-BR.
+func languageCode(voice string) string {
+	parts := strings.SplitN(voice, "-", 3)
+	if len(parts) < 2 {
+		return "pt-BR"
+	}
+	return parts[0] + "-" + parts[1]
+}
@@ -2,17 +2,18 @@ package integrations
 
 import (
 	"bytes"
+	"context"
 	"log"
 	"net/http"
 	"time"
 )
 
-func TriggerHomeAssistant(webhookURL string, payload string) error {
+func TriggerHomeAssistant(ctx context.Context, webhookURL string, payload string) error {
 	if webhookURL == "" || webhookURL == "http://SEU_HOME_ASSISTANT_IP:8123/api/webhook/SEU_WEBHOOK_ID" {
 		log.Println("URL do Home Assistant não configurada. Pulando webhook.")
 		return nil
 	}
-	req, _ := http.NewRequest("POST", webhookURL, bytes.NewBuffer([]byte(payload)))
+	req, _ := http.NewRequestWithContext(ctx, "POST", webhookURL, bytes.NewBuffer([]byte(payload)))
 	req.Header.Set("Content-Type", "application/json")
 	client := &http.Client{Timeout: 10 * time.Second}
 	resp, err := client.Do(req)
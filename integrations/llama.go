@@ -2,6 +2,7 @@ package integrations
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -72,24 +73,31 @@ func (pm *PromptManager) FormatPrompt(instruction string) string {
 	return fmt.Sprintf("%s %s", pm.basePrompt, instruction)
 }
 
-func (pm *PromptManager) FormatPromptWithLevel(level string, instruction string) string {
+// FormatPromptWithLevel monta o prompt com o nível de hiperfoco e, quando houver
+// um plano de voo ativo, a tarefa declarada pelo piloto.
+func (pm *PromptManager) FormatPromptWithLevel(level string, task string, instruction string) string {
+	planContext := ""
+	if task != "" {
+		planContext = fmt.Sprintf("\nPlano de voo declarado pelo piloto: \"%s\" (mencione a tarefa na mensagem)", task)
+	}
 	finalPrompt := fmt.Sprintf(
-		"%s\n\nNível de Hiperfoco detectado: %s\nInstrução do sistema: \"%s\"",
+		"%s\n\nNível de Hiperfoco detectado: %s%s\nInstrução do sistema: \"%s\"",
 		pm.basePrompt,
 		level,
+		planContext,
 		instruction,
 	)
 	return finalPrompt
 }
 
-func GenerateTextWithLlama(model, prompt string) (string, error) {
+func GenerateTextWithLlama(ctx context.Context, model, prompt string) (string, error) {
 	endpoint := getOllamaEndpoint()
 	requestData := OllamaRequest{Model: model, Prompt: prompt, Stream: false}
 	jsonData, err := json.Marshal(requestData)
 	if err != nil {
 		return "", fmt.Errorf("erro ao converter para JSON: %w", err)
 	}
-	req, err := http.NewRequest("POST", endpoint, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewBuffer(jsonData))
 	if err != nil {
 		return "", fmt.Errorf("erro ao criar HTTP request: %w", err)
 	}
@@ -0,0 +1,52 @@
+package integrations
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	logindService = "org.freedesktop.login1"
+	logindPath    = "/org/freedesktop/login1"
+)
+
+// LockSession bloqueia a sessão gráfica atual pelo logind. Com busAddress vazio
+// usa o barramento do sistema; um endereço explícito permite apontar para um
+// logind simulado em um barramento privado.
+func LockSession(ctx context.Context, busAddress string) error {
+	conn, err := connectBus(busAddress)
+	if err != nil {
+		return fmt.Errorf("erro ao conectar ao D-Bus: %w", err)
+	}
+	defer conn.Close()
+
+	manager := conn.Object(logindService, logindPath)
+	if sessionID := os.Getenv("XDG_SESSION_ID"); sessionID != "" {
+		call := manager.CallWithContext(ctx, logindService+".Manager.LockSession", 0, sessionID)
+		if call.Err != nil {
+			return fmt.Errorf("LockSession(%s): %w", sessionID, call.Err)
+		}
+		return nil
+	}
+
+	var sessionPath dbus.ObjectPath
+	err = manager.CallWithContext(ctx, logindService+".Manager.GetSessionByPID", 0, uint32(os.Getpid())).Store(&sessionPath)
+	if err != nil {
+		return fmt.Errorf("GetSessionByPID: %w", err)
+	}
+	call := conn.Object(logindService, sessionPath).CallWithContext(ctx, logindService+".Session.Lock", 0)
+	if call.Err != nil {
+		return fmt.Errorf("Session.Lock(%s): %w", sessionPath, call.Err)
+	}
+	return nil
+}
+
+func connectBus(address string) (*dbus.Conn, error) {
+	if address == "" {
+		return dbus.ConnectSystemBus()
+	}
+	return dbus.Connect(address)
+}
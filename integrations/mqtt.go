@@ -0,0 +1,146 @@
+package integrations
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// MQTTBroker identifica a conexão com um broker MQTT.
+type MQTTBroker struct {
+	URL      string // ex.: tcp://localhost:1883
+	ClientID string
+	Username string
+	Password string
+	Will     *MQTTMessage // mensagem publicada pelo broker se a conexão cair
+}
+
+// MQTTMessage é uma publicação em um tópico MQTT.
+type MQTTMessage struct {
+	Topic   string
+	Payload []byte
+	QoS     byte
+	Retain  bool
+}
+
+// mqttConn é a conexão compartilhada com um broker. A primeira chamada cria o
+// cliente e dispara uma única tentativa de conexão; as demais esperam ready.
+type mqttConn struct {
+	client mqtt.Client
+	ready  chan struct{} // fechado quando a tentativa de conexão termina
+	err    error
+}
+
+var (
+	mqttMu      sync.Mutex
+	mqttClients = make(map[MQTTBroker]*mqttConn)
+)
+
+// PublishMQTT publica a mensagem, reaproveitando a conexão com o broker entre chamadas.
+func PublishMQTT(ctx context.Context, broker MQTTBroker, msg MQTTMessage) error {
+	if broker.URL == "" {
+		return fmt.Errorf("broker MQTT não configurado")
+	}
+	if msg.QoS > 2 {
+		return fmt.Errorf("QoS MQTT inválido: %d", msg.QoS)
+	}
+	client, err := mqttClient(ctx, broker)
+	if err != nil {
+		return err
+	}
+	token := client.Publish(msg.Topic, msg.QoS, msg.Retain, msg.Payload)
+	if err := waitMQTT(ctx, token); err != nil {
+		return fmt.Errorf("erro ao publicar em %s: %w", msg.Topic, err)
+	}
+	return nil
+}
+
+// CloseMQTT encerra todas as conexões abertas com brokers MQTT.
+func CloseMQTT() {
+	mqttMu.Lock()
+	defer mqttMu.Unlock()
+	for broker, conn := range mqttClients {
+		conn.client.Disconnect(250)
+		delete(mqttClients, broker)
+	}
+}
+
+// mqttClient retorna o cliente conectado ao broker. A conexão acontece fora de
+// mqttMu, então um broker lento não trava as publicações em outros brokers nem
+// o CloseMQTT.
+func mqttClient(ctx context.Context, broker MQTTBroker) (mqtt.Client, error) {
+	mqttMu.Lock()
+	conn, ok := mqttClients[broker]
+	if !ok {
+		conn = &mqttConn{client: newMQTTClient(broker), ready: make(chan struct{})}
+		mqttClients[broker] = conn
+		go conn.connect(broker)
+	}
+	mqttMu.Unlock()
+
+	select {
+	case <-conn.ready:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if conn.err != nil {
+		return nil, fmt.Errorf("erro ao conectar ao broker MQTT %s: %w", broker.URL, conn.err)
+	}
+	return conn.client, nil
+}
+
+func newMQTTClient(broker MQTTBroker) mqtt.Client {
+	opts := mqtt.NewClientOptions().
+		AddBroker(broker.URL).
+		SetClientID(broker.ClientID).
+		SetUsername(broker.Username).
+		SetPassword(broker.Password).
+		SetConnectTimeout(10 * time.Second).
+		SetAutoReconnect(true).
+		SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+			log.Printf("Conexão MQTT com %s perdida: %v", broker.URL, err)
+		})
+	if broker.Will != nil {
+		opts.SetBinaryWill(broker.Will.Topic, broker.Will.Payload, broker.Will.QoS, broker.Will.Retain)
+	}
+	return mqtt.NewClient(opts)
+}
+
+// connect faz a tentativa de conexão sem o contexto de quem pediu o cliente,
+// limitada pelo ConnectTimeout. Se falhar, a entrada sai do mapa e a próxima
+// publicação tenta de novo; se o CloseMQTT já tiver removido a entrada, a
+// conexão recém-aberta é encerrada.
+func (c *mqttConn) connect(broker MQTTBroker) {
+	token := c.client.Connect()
+	token.Wait()
+	c.err = token.Error()
+
+	mqttMu.Lock()
+	current := mqttClients[broker] == c
+	if c.err != nil && current {
+		delete(mqttClients, broker)
+	}
+	mqttMu.Unlock()
+
+	if c.err == nil {
+		if current {
+			log.Printf("Conectado ao broker MQTT %s.", broker.URL)
+		} else {
+			c.client.Disconnect(250)
+		}
+	}
+	close(c.ready)
+}
+
+func waitMQTT(ctx context.Context, token mqtt.Token) error {
+	select {
+	case <-token.Done():
+		return token.Error()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,236 @@
+package integrations
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	mqttserver "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/listeners"
+	"github.com/mochi-mqtt/server/v2/packets"
+)
+
+// connectRecorder aceita qualquer cliente e guarda os pacotes CONNECT.
+type connectRecorder struct {
+	mqttserver.HookBase
+	mu       sync.Mutex
+	connects []packets.Packet
+}
+
+func (h *connectRecorder) ID() string { return "connect-recorder" }
+
+func (h *connectRecorder) Provides(b byte) bool {
+	return b == mqttserver.OnConnectAuthenticate || b == mqttserver.OnACLCheck
+}
+
+func (h *connectRecorder) OnConnectAuthenticate(_ *mqttserver.Client, pk packets.Packet) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.connects = append(h.connects, pk)
+	return true
+}
+
+func (h *connectRecorder) OnACLCheck(*mqttserver.Client, string, bool) bool { return true }
+
+func (h *connectRecorder) count() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.connects)
+}
+
+// startBroker sobe um broker embutido em addr ("127.0.0.1:0" escolhe a porta)
+// e retorna a URL tcp:// dele.
+func startBroker(t *testing.T, addr string) (*mqttserver.Server, *connectRecorder, string) {
+	t.Helper()
+	server := mqttserver.New(&mqttserver.Options{
+		InlineClient: true,
+		Logger:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	recorder := &connectRecorder{}
+	if err := server.AddHook(recorder, nil); err != nil {
+		t.Fatal(err)
+	}
+	tcp := listeners.NewTCP(listeners.Config{ID: "tcp", Address: addr})
+	if err := server.AddListener(tcp); err != nil {
+		t.Fatal(err)
+	}
+	go server.Serve()
+	t.Cleanup(func() {
+		CloseMQTT()
+		server.Close()
+	})
+	return server, recorder, "tcp://" + tcp.Address()
+}
+
+func TestPublishMQTT(t *testing.T) {
+	server, recorder, url := startBroker(t, "127.0.0.1:0")
+	received := make(chan packets.Packet, 4)
+	err := server.Subscribe("focus-helper/#", 1, func(_ *mqttserver.Client, _ packets.Subscription, pk packets.Packet) {
+		received <- pk
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	broker := MQTTBroker{
+		URL:      url,
+		ClientID: "focus-helper-teste",
+		Username: "piloto",
+		Password: "segredo",
+		Will:     &MQTTMessage{Topic: "focus-helper/teste/state", Payload: []byte(`{"online":false}`), QoS: 1, Retain: true},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for _, payload := range []string{"primeiro", "segundo"} {
+		msg := MQTTMessage{Topic: "focus-helper/alerts/HIGH", Payload: []byte(payload), QoS: 1, Retain: true}
+		if err := PublishMQTT(ctx, broker, msg); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case pk := <-received:
+			if pk.TopicName != msg.Topic || string(pk.Payload) != payload {
+				t.Errorf("recebido %s %q, esperado %s %q", pk.TopicName, pk.Payload, msg.Topic, payload)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("mensagem %q não chegou ao broker", payload)
+		}
+	}
+
+	retained := server.Topics.Messages("focus-helper/alerts/HIGH")
+	if len(retained) != 1 || string(retained[0].Payload) != "segundo" {
+		t.Errorf("mensagens retidas = %v, esperado a última publicação", retained)
+	}
+	if n := recorder.count(); n != 1 {
+		t.Fatalf("%d conexões, esperado uma conexão reaproveitada", n)
+	}
+	connect := recorder.connects[0].Connect
+	if connect.ClientIdentifier != broker.ClientID || string(connect.Username) != broker.Username || string(connect.Password) != broker.Password {
+		t.Errorf("CONNECT com cliente %q, usuário %q, senha %q", connect.ClientIdentifier, connect.Username, connect.Password)
+	}
+	if !connect.WillFlag || connect.WillTopic != broker.Will.Topic || string(connect.WillPayload) != string(broker.Will.Payload) ||
+		connect.WillQos != broker.Will.QoS || !connect.WillRetain {
+		t.Errorf("last will = %q %q qos %d retain %v", connect.WillTopic, connect.WillPayload, connect.WillQos, connect.WillRetain)
+	}
+}
+
+func TestPublishMQTTConnectsOnce(t *testing.T) {
+	_, recorder, url := startBroker(t, "127.0.0.1:0")
+	broker := MQTTBroker{URL: url, ClientID: "focus-helper-concorrente"}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- PublishMQTT(ctx, broker, MQTTMessage{Topic: "focus-helper/teste", Payload: []byte("x")})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	if n := recorder.count(); n != 1 {
+		t.Errorf("%d conexões para publicações simultâneas, esperado 1", n)
+	}
+}
+
+// TestPublishMQTTSlowBroker usa um servidor que aceita a conexão TCP e nunca
+// responde o CONNECT: a espera não pode travar outros brokers nem o CloseMQTT.
+func TestPublishMQTTSlowBroker(t *testing.T) {
+	silent, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer silent.Close()
+	go func() {
+		var conns []net.Conn
+		defer func() {
+			for _, conn := range conns {
+				conn.Close()
+			}
+		}()
+		for {
+			conn, err := silent.Accept()
+			if err != nil {
+				return
+			}
+			conns = append(conns, conn)
+		}
+	}()
+	_, _, url := startBroker(t, "127.0.0.1:0")
+
+	slowCtx, cancelSlow := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancelSlow()
+	slowDone := make(chan error, 1)
+	go func() {
+		slowDone <- PublishMQTT(slowCtx, MQTTBroker{URL: "tcp://" + silent.Addr().String(), ClientID: "lento"}, MQTTMessage{Topic: "t"})
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	time.Sleep(50 * time.Millisecond) // o broker lento já está conectando
+	if err := PublishMQTT(ctx, MQTTBroker{URL: url, ClientID: "rapido"}, MQTTMessage{Topic: "t"}); err != nil {
+		t.Errorf("publicação no broker disponível: %v", err)
+	}
+	if err := <-slowDone; !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("broker lento retornou %v, esperado o prazo do contexto", err)
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		CloseMQTT()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(2 * time.Second):
+		t.Error("CloseMQTT travou esperando a conexão pendente")
+	}
+}
+
+func TestPublishMQTTRetriesAfterFailedConnect(t *testing.T) {
+	// Reserva uma porta e a libera, para a primeira conexão ser recusada.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	broker := MQTTBroker{URL: "tcp://" + addr, ClientID: "focus-helper-retry"}
+	msg := MQTTMessage{Topic: "focus-helper/teste", Payload: []byte("x")}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := PublishMQTT(ctx, broker, msg); err == nil {
+		t.Fatal("publicação sem broker deveria falhar")
+	}
+
+	_, recorder, _ := startBroker(t, addr)
+	if err := PublishMQTT(ctx, broker, msg); err != nil {
+		t.Fatalf("nova tentativa após o broker subir: %v", err)
+	}
+	if n := recorder.count(); n != 1 {
+		t.Errorf("%d conexões, esperado 1", n)
+	}
+}
+
+func TestPublishMQTTInvalid(t *testing.T) {
+	ctx := context.Background()
+	if err := PublishMQTT(ctx, MQTTBroker{}, MQTTMessage{Topic: "t"}); err == nil {
+		t.Error("broker vazio deveria falhar")
+	}
+	if err := PublishMQTT(ctx, MQTTBroker{URL: "tcp://127.0.0.1:1"}, MQTTMessage{Topic: "t", QoS: 3}); err == nil {
+		t.Error("QoS 3 deveria falhar")
+	}
+}
@@ -0,0 +1,313 @@
+package integrations
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// SuspendedProcess é um processo parado com SIGSTOP. StartTime (em ticks desde
+// o boot) evita retomar outro processo que tenha reutilizado o mesmo PID. Owner
+// e OwnerStart identificam a instância do Focus Helper que o suspendeu.
+type SuspendedProcess struct {
+	PID         int       `json:"pid"`
+	StartTime   uint64    `json:"start_time"`
+	Name        string    `json:"name"`
+	SuspendedAt time.Time `json:"suspended_at"`
+	Owner       int       `json:"owner"`
+	OwnerStart  uint64    `json:"owner_start"`
+}
+
+// suspendKey identifica um registro: a instância dona e o processo suspenso.
+// Só o PID não basta, já que outra instância pode ter suspendido um processo
+// que reutilizou o mesmo PID.
+type suspendKey struct {
+	Owner      int
+	OwnerStart uint64
+	PID        int
+	StartTime  uint64
+}
+
+func (p SuspendedProcess) key() suspendKey {
+	return suspendKey{Owner: p.Owner, OwnerStart: p.OwnerStart, PID: p.PID, StartTime: p.StartTime}
+}
+
+var suspendMutex sync.Mutex
+
+// FindProcesses retorna os processos do usuário atual cujo nome ou linha de
+// comando casam com match e não casam com allow. O próprio Focus Helper e o
+// init nunca entram.
+func FindProcesses(match, allow []*regexp.Regexp) ([]SuspendedProcess, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, err
+	}
+	self, uid := os.Getpid(), os.Getuid()
+	var found []SuspendedProcess
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil || pid == 1 || pid == self {
+			continue
+		}
+		if owner, err := processUID(pid); err != nil || owner != uid {
+			continue
+		}
+		name, cmdline, startTime, err := readProcess(pid)
+		if err != nil {
+			continue
+		}
+		if !matchesAny(match, name, cmdline) || matchesAny(allow, name, cmdline) {
+			continue
+		}
+		found = append(found, SuspendedProcess{PID: pid, StartTime: startTime, Name: name})
+	}
+	return found, nil
+}
+
+// SuspendProcesses envia SIGSTOP aos processos. Cada PID é gravado no arquivo
+// de registro antes de ser parado, para que um crash não deixe nada congelado.
+func SuspendProcesses(stateFile string, procs []SuspendedProcess) []SuspendedProcess {
+	suspendMutex.Lock()
+	defer suspendMutex.Unlock()
+	unlock, err := lockSuspendState(stateFile)
+	if err != nil {
+		log.Printf("Erro ao travar registro de processos suspensos, nada será suspenso: %v", err)
+		return nil
+	}
+	defer unlock()
+	records, err := readSuspended(stateFile)
+	if err != nil {
+		log.Printf("Erro ao ler registro de processos suspensos: %v", err)
+	}
+	owner := os.Getpid()
+	_, _, ownerStart, err := readProcess(owner)
+	if err != nil {
+		log.Printf("Erro ao ler o próprio processo, nada será suspenso: %v", err)
+		return nil
+	}
+	var suspended []SuspendedProcess
+	for _, proc := range procs {
+		proc.SuspendedAt = time.Now()
+		proc.Owner, proc.OwnerStart = owner, ownerStart
+		if err := writeSuspended(stateFile, append(records, proc)); err != nil {
+			log.Printf("Erro ao gravar registro de processos suspensos, %s (%d) não será suspenso: %v", proc.Name, proc.PID, err)
+			continue
+		}
+		if err := syscall.Kill(proc.PID, syscall.SIGSTOP); err != nil {
+			log.Printf("Erro ao suspender %s (%d): %v", proc.Name, proc.PID, err)
+			continue
+		}
+		log.Printf("Processo suspenso: %s (%d)", proc.Name, proc.PID)
+		records = append(records, proc)
+		suspended = append(suspended, proc)
+	}
+	if err := writeSuspended(stateFile, records); err != nil {
+		log.Printf("Erro ao gravar registro de processos suspensos: %v", err)
+	}
+	return suspended
+}
+
+// ResumeProcesses envia SIGCONT aos processos e os remove do registro.
+func ResumeProcesses(stateFile string, procs []SuspendedProcess) {
+	suspendMutex.Lock()
+	defer suspendMutex.Unlock()
+	unlock, err := lockSuspendState(stateFile)
+	if err != nil {
+		log.Printf("Erro ao travar registro de processos suspensos: %v", err)
+	} else {
+		defer unlock()
+	}
+	records, err := readSuspended(stateFile)
+	if err != nil {
+		log.Printf("Erro ao ler registro de processos suspensos: %v", err)
+	}
+	resumed := make(map[suspendKey]bool)
+	for _, proc := range procs {
+		resumeProcess(proc)
+		resumed[proc.key()] = true
+	}
+	var remaining []SuspendedProcess
+	for _, record := range records {
+		if !resumed[record.key()] {
+			remaining = append(remaining, record)
+		}
+	}
+	if err := writeSuspended(stateFile, remaining); err != nil {
+		log.Printf("Erro ao gravar registro de processos suspensos: %v", err)
+	}
+}
+
+// ResumeAllSuspended retoma os processos do registro suspensos por esta
+// instância ou por uma que já terminou. Deve rodar na inicialização (após um
+// crash) e no encerramento; os processos de outra instância em execução
+// continuam suspensos por ela.
+func ResumeAllSuspended(stateFile string) {
+	suspendMutex.Lock()
+	defer suspendMutex.Unlock()
+	unlock, err := lockSuspendState(stateFile)
+	if err != nil {
+		log.Printf("Erro ao travar registro de processos suspensos: %v", err)
+		return
+	}
+	defer unlock()
+	records, err := readSuspended(stateFile)
+	if err != nil {
+		log.Printf("Erro ao ler registro de processos suspensos: %v", err)
+		return
+	}
+	var remaining []SuspendedProcess
+	for _, record := range records {
+		if ownedByOtherInstance(record) {
+			log.Printf("Processo %s (%d) suspenso por outra instância (%d), mantido.", record.Name, record.PID, record.Owner)
+			remaining = append(remaining, record)
+			continue
+		}
+		resumeProcess(record)
+	}
+	if err := writeSuspended(stateFile, remaining); err != nil {
+		log.Printf("Erro ao limpar registro de processos suspensos: %v", err)
+	}
+}
+
+// ownedByOtherInstance indica se o registro pertence a outra instância do
+// Focus Helper que ainda está rodando.
+func ownedByOtherInstance(record SuspendedProcess) bool {
+	if record.Owner == 0 || record.Owner == os.Getpid() {
+		return false
+	}
+	_, _, startTime, err := readProcess(record.Owner)
+	return err == nil && startTime == record.OwnerStart
+}
+
+// lockSuspendState trava o registro de processos suspensos entre instâncias
+// com flock. A trava some sozinha se o processo morrer.
+func lockSuspendState(stateFile string) (func(), error) {
+	f, err := os.OpenFile(stateFile+".lock", os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return func() {
+		_ = syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		f.Close()
+	}, nil
+}
+
+func resumeProcess(proc SuspendedProcess) {
+	_, _, startTime, err := readProcess(proc.PID)
+	if err != nil || startTime != proc.StartTime {
+		log.Printf("Processo %s (%d) não existe mais, nada a retomar.", proc.Name, proc.PID)
+		return
+	}
+	if err := syscall.Kill(proc.PID, syscall.SIGCONT); err != nil {
+		log.Printf("Erro ao retomar %s (%d): %v", proc.Name, proc.PID, err)
+		return
+	}
+	log.Printf("Processo retomado: %s (%d)", proc.Name, proc.PID)
+}
+
+func readProcess(pid int) (name, cmdline string, startTime uint64, err error) {
+	dir := filepath.Join("/proc", strconv.Itoa(pid))
+	stat, err := os.ReadFile(filepath.Join(dir, "stat"))
+	if err != nil {
+		return "", "", 0, err
+	}
+	// O nome do processo fica entre parênteses e pode conter espaços.
+	open, end := strings.IndexByte(string(stat), '('), strings.LastIndexByte(string(stat), ')')
+	if open < 0 || end < open {
+		return "", "", 0, fmt.Errorf("formato inesperado em %s/stat", dir)
+	}
+	name = string(stat[open+1 : end])
+	fields := strings.Fields(string(stat[end+1:]))
+	if len(fields) < 20 {
+		return "", "", 0, fmt.Errorf("formato inesperado em %s/stat", dir)
+	}
+	startTime, err = strconv.ParseUint(fields[19], 10, 64)
+	if err != nil {
+		return "", "", 0, err
+	}
+	raw, _ := os.ReadFile(filepath.Join(dir, "cmdline"))
+	cmdline = strings.TrimSpace(strings.ReplaceAll(string(raw), "\x00", " "))
+	return name, cmdline, startTime, nil
+}
+
+// processUID retorna o UID real do dono do processo, da linha "Uid:" de
+// /proc/<pid>/status.
+func processUID(pid int) (int, error) {
+	status, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "status"))
+	if err != nil {
+		return 0, err
+	}
+	for _, line := range strings.Split(string(status), "\n") {
+		if fields := strings.Fields(line); len(fields) >= 2 && fields[0] == "Uid:" {
+			return strconv.Atoi(fields[1])
+		}
+	}
+	return 0, fmt.Errorf("Uid ausente em /proc/%d/status", pid)
+}
+
+func matchesAny(patterns []*regexp.Regexp, name, cmdline string) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(name) || (cmdline != "" && pattern.MatchString(cmdline)) {
+			return true
+		}
+	}
+	return false
+}
+
+func readSuspended(stateFile string) ([]SuspendedProcess, error) {
+	data, err := os.ReadFile(stateFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var records []SuspendedProcess
+	err = json.Unmarshal(data, &records)
+	return records, err
+}
+
+func writeSuspended(stateFile string, records []SuspendedProcess) error {
+	if len(records) == 0 {
+		err := os.Remove(stateFile)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	data, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+	tmp := stateFile + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	// fsync antes do rename: o registro precisa existir antes do SIGSTOP.
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, stateFile)
+}
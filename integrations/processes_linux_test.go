@@ -0,0 +1,160 @@
+package integrations
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// startSleep inicia um processo filho que dura o teste inteiro.
+func startSleep(t *testing.T) *exec.Cmd {
+	t.Helper()
+	cmd := exec.Command("sleep", "60")
+	if err := cmd.Start(); err != nil {
+		t.Skip("sleep não disponível: ", err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Signal(syscall.SIGCONT)
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+	return cmd
+}
+
+// stopped indica se o processo está parado por sinal (estado T em /proc).
+func stopped(t *testing.T, pid int) bool {
+	t.Helper()
+	stat, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "stat"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fields := strings.Fields(string(stat[strings.LastIndexByte(string(stat), ')')+1:]))
+	return fields[0] == "T"
+}
+
+// waitStopped espera o sinal assíncrono levar o processo ao estado esperado.
+func waitStopped(t *testing.T, pid int, want bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for stopped(t, pid) != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("processo %d parado = %v, esperado %v", pid, !want, want)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestFindProcesses(t *testing.T) {
+	cmd := startSleep(t)
+	match := []*regexp.Regexp{regexp.MustCompile(`^sleep$`)}
+	found, err := FindProcesses(match, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var proc *SuspendedProcess
+	for i := range found {
+		if found[i].PID == cmd.Process.Pid {
+			proc = &found[i]
+		}
+	}
+	if proc == nil || proc.Name != "sleep" || proc.StartTime == 0 {
+		t.Fatalf("sleep (%d) não encontrado em %+v", cmd.Process.Pid, found)
+	}
+	allowed, _ := FindProcesses(match, []*regexp.Regexp{regexp.MustCompile(`sleep 60`)})
+	for _, p := range allowed {
+		if p.PID == cmd.Process.Pid {
+			t.Error("processo da allowlist encontrado")
+		}
+	}
+}
+
+func TestResumeAllSuspendedKeepsOtherInstance(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "suspended.json")
+	own := startSleep(t)
+	_, _, ownStart, err := readProcess(own.Process.Pid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	suspended := SuspendProcesses(stateFile, []SuspendedProcess{{PID: own.Process.Pid, StartTime: ownStart, Name: "sleep"}})
+	if len(suspended) != 1 || suspended[0].Owner != os.Getpid() {
+		t.Fatalf("suspensos = %+v", suspended)
+	}
+	waitStopped(t, own.Process.Pid, true)
+
+	// Um processo parado por outra instância em execução, simulada por outro filho.
+	otherDaemon := startSleep(t)
+	_, _, daemonStart, _ := readProcess(otherDaemon.Process.Pid)
+	foreign := startSleep(t)
+	_, _, foreignStart, _ := readProcess(foreign.Process.Pid)
+	if err := syscall.Kill(foreign.Process.Pid, syscall.SIGSTOP); err != nil {
+		t.Fatal(err)
+	}
+	records, _ := readSuspended(stateFile)
+	records = append(records, SuspendedProcess{PID: foreign.Process.Pid, StartTime: foreignStart, Name: "sleep",
+		Owner: otherDaemon.Process.Pid, OwnerStart: daemonStart})
+	if err := writeSuspended(stateFile, records); err != nil {
+		t.Fatal(err)
+	}
+
+	ResumeAllSuspended(stateFile)
+	waitStopped(t, own.Process.Pid, false)
+	waitStopped(t, foreign.Process.Pid, true)
+	records, _ = readSuspended(stateFile)
+	if len(records) != 1 || records[0].PID != foreign.Process.Pid {
+		t.Fatalf("registro depois da retomada = %+v", records)
+	}
+
+	// Quando a outra instância termina, o processo dela fica órfão e é retomado.
+	otherDaemon.Process.Kill()
+	otherDaemon.Wait()
+	ResumeAllSuspended(stateFile)
+	waitStopped(t, foreign.Process.Pid, false)
+	if _, err := os.Stat(stateFile); !os.IsNotExist(err) {
+		t.Errorf("registro não foi removido: %v", err)
+	}
+}
+
+func TestProcessUID(t *testing.T) {
+	cmd := startSleep(t)
+	uid, err := processUID(cmd.Process.Pid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uid != os.Getuid() {
+		t.Errorf("UID = %d, esperado %d", uid, os.Getuid())
+	}
+}
+
+func TestResumeProcessesKeepsOtherInstanceWithSamePID(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "suspended.json")
+	own := startSleep(t)
+	_, _, ownStart, err := readProcess(own.Process.Pid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	suspended := SuspendProcesses(stateFile, []SuspendedProcess{{PID: own.Process.Pid, StartTime: ownStart, Name: "sleep"}})
+	if len(suspended) != 1 {
+		t.Fatalf("suspensos = %+v", suspended)
+	}
+	waitStopped(t, own.Process.Pid, true)
+
+	// Registro de outra instância para o mesmo PID, com outro dono.
+	foreign := SuspendedProcess{PID: own.Process.Pid, StartTime: ownStart, Name: "sleep", Owner: os.Getpid() + 1, OwnerStart: 42}
+	records, _ := readSuspended(stateFile)
+	if err := writeSuspended(stateFile, append(records, foreign)); err != nil {
+		t.Fatal(err)
+	}
+
+	ResumeProcesses(stateFile, suspended)
+	waitStopped(t, own.Process.Pid, false)
+	records, _ = readSuspended(stateFile)
+	if len(records) != 1 || records[0].key() != foreign.key() {
+		t.Fatalf("registro depois da retomada = %+v", records)
+	}
+}
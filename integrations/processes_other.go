@@ -0,0 +1,31 @@
+//go:build !linux
+
+package integrations
+
+import (
+	"fmt"
+	"regexp"
+	"runtime"
+	"time"
+)
+
+type SuspendedProcess struct {
+	PID         int       `json:"pid"`
+	StartTime   uint64    `json:"start_time"`
+	Name        string    `json:"name"`
+	SuspendedAt time.Time `json:"suspended_at"`
+	Owner       int       `json:"owner"`
+	OwnerStart  uint64    `json:"owner_start"`
+}
+
+func FindProcesses(match, allow []*regexp.Regexp) ([]SuspendedProcess, error) {
+	return nil, fmt.Errorf("suspensão de processos não suportada em %s", runtime.GOOS)
+}
+
+func SuspendProcesses(stateFile string, procs []SuspendedProcess) []SuspendedProcess {
+	return nil
+}
+
+func ResumeProcesses(stateFile string, procs []SuspendedProcess) {}
+
+func ResumeAllSuspended(stateFile string) {}
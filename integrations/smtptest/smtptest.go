@@ -0,0 +1,157 @@
+// Package smtptest fornece um servidor SMTP local que captura as mensagens
+// recebidas, para testes que enviam e-mail.
+package smtptest
+
+import (
+	"encoding/base64"
+	"io"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Message é uma mensagem recebida pelo servidor.
+type Message struct {
+	From     string   // endereço do MAIL FROM
+	To       []string // endereços dos RCPT TO
+	Username string   // usuário do AUTH PLAIN, se houve autenticação
+	Password string
+	Data     []byte // conteúdo do DATA, sem o ponto final
+}
+
+// Parse interpreta os cabeçalhos e o corpo da mensagem.
+func (m Message) Parse() (*mail.Message, error) {
+	return mail.ReadMessage(strings.NewReader(string(m.Data)))
+}
+
+// Server é um servidor SMTP em 127.0.0.1 que aceita qualquer remetente,
+// destinatário e credencial.
+type Server struct {
+	Host     string
+	Port     int
+	Messages chan Message
+
+	listener net.Listener
+	wg       sync.WaitGroup
+}
+
+// NewServer inicia o servidor; ele é encerrado no fim do teste.
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := l.Addr().(*net.TCPAddr)
+	s := &Server{Host: addr.IP.String(), Port: addr.Port, Messages: make(chan Message, 16), listener: l}
+	s.wg.Add(1)
+	go s.serve()
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Close para de aceitar conexões e espera as sessões em andamento.
+func (s *Server) Close() {
+	s.listener.Close()
+	s.wg.Wait()
+}
+
+// Next aguarda a próxima mensagem recebida.
+func (s *Server) Next(t testing.TB) Message {
+	t.Helper()
+	select {
+	case msg := <-s.Messages:
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("nenhuma mensagem recebida pelo servidor SMTP")
+		return Message{}
+	}
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer conn.Close()
+			conn.SetDeadline(time.Now().Add(10 * time.Second))
+			s.session(textproto.NewConn(conn))
+		}()
+	}
+}
+
+func (s *Server) session(c *textproto.Conn) {
+	c.PrintfLine("220 localhost ESMTP smtptest")
+	var msg Message
+	for {
+		line, err := c.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			c.PrintfLine("250-localhost")
+			c.PrintfLine("250-8BITMIME")
+			c.PrintfLine("250 AUTH PLAIN")
+		case "AUTH":
+			mechanism, initial, _ := strings.Cut(arg, " ")
+			creds, err := base64.StdEncoding.DecodeString(initial)
+			parts := strings.Split(string(creds), "\x00")
+			if !strings.EqualFold(mechanism, "PLAIN") || err != nil || len(parts) != 3 {
+				c.PrintfLine("535 credenciais inválidas")
+				continue
+			}
+			msg.Username, msg.Password = parts[1], parts[2]
+			c.PrintfLine("235 autenticado")
+		case "MAIL":
+			msg.From = angleAddress(arg)
+			c.PrintfLine("250 ok")
+		case "RCPT":
+			msg.To = append(msg.To, angleAddress(arg))
+			c.PrintfLine("250 ok")
+		case "DATA":
+			c.PrintfLine("354 envie a mensagem")
+			data, err := io.ReadAll(c.DotReader())
+			if err != nil {
+				return
+			}
+			msg.Data = data
+			s.Messages <- msg
+			msg = Message{}
+			c.PrintfLine("250 ok")
+		case "RSET":
+			msg = Message{}
+			c.PrintfLine("250 ok")
+		case "NOOP":
+			c.PrintfLine("250 ok")
+		case "QUIT":
+			c.PrintfLine("221 tchau")
+			return
+		default:
+			c.PrintfLine("502 comando não implementado")
+		}
+	}
+}
+
+// angleAddress extrai o endereço de "FROM:<endereço> PARAM=...".
+func angleAddress(arg string) string {
+	_, rest, _ := strings.Cut(arg, "<")
+	address, _, _ := strings.Cut(rest, ">")
+	return address
+}
+
+// Addr retorna host:porta do servidor.
+func (s *Server) Addr() string {
+	return net.JoinHostPort(s.Host, strconv.Itoa(s.Port))
+}
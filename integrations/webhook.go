@@ -0,0 +1,97 @@
+package integrations
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+// WebhookRequest descreve uma chamada HTTP genérica com autenticação e novas tentativas.
+type WebhookRequest struct {
+	URL           string
+	Method        string
+	Headers       map[string]string
+	Body          []byte
+	BearerToken   string
+	BasicUser     string
+	BasicPassword string
+	Retries       int
+	RetryDelay    time.Duration
+	Timeout       time.Duration
+}
+
+// SendWebhook envia a requisição, tentando de novo em erros de rede, 429 e 5xx.
+func SendWebhook(ctx context.Context, req WebhookRequest) error {
+	if req.URL == "" {
+		return fmt.Errorf("URL do webhook não configurada")
+	}
+	if req.Method == "" {
+		req.Method = http.MethodPost
+	}
+	if req.Timeout <= 0 {
+		req.Timeout = 10 * time.Second
+	}
+	delay := req.RetryDelay
+	if delay <= 0 {
+		delay = 2 * time.Second
+	}
+	client := &http.Client{Timeout: req.Timeout}
+
+	var lastErr error
+	for attempt := 0; attempt <= req.Retries; attempt++ {
+		if attempt > 0 {
+			log.Printf("Webhook %s falhou (%v). Nova tentativa %d de %d em %v.", req.URL, lastErr, attempt, req.Retries, delay)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(delay):
+			}
+			delay *= 2
+		}
+		retry, err := sendWebhookOnce(ctx, client, req)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retry || ctx.Err() != nil {
+			break
+		}
+	}
+	return lastErr
+}
+
+func sendWebhookOnce(ctx context.Context, client *http.Client, req WebhookRequest) (bool, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, req.URL, bytes.NewReader(req.Body))
+	if err != nil {
+		return false, fmt.Errorf("erro ao criar HTTP request: %w", err)
+	}
+	if len(req.Body) > 0 {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	for key, value := range req.Headers {
+		httpReq.Header.Set(key, value)
+	}
+	switch {
+	case req.BearerToken != "":
+		httpReq.Header.Set("Authorization", "Bearer "+req.BearerToken)
+	case req.BasicUser != "":
+		httpReq.SetBasicAuth(req.BasicUser, req.BasicPassword)
+	}
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return true, fmt.Errorf("erro ao enviar webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		log.Printf("Webhook enviado para %s. Status: %s", req.URL, resp.Status)
+		return false, nil
+	}
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("webhook retornou status %s - Body: %s", resp.Status, string(body))
+}
@@ -0,0 +1,146 @@
+package integrations
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSendWebhookRequest(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        WebhookRequest
+		wantMethod string
+		wantHeader map[string]string
+	}{
+		{
+			name:       "padrão POST com bearer",
+			req:        WebhookRequest{Body: []byte(`{"level":"HIGH"}`), BearerToken: "segredo", Headers: map[string]string{"X-Focus": "1"}},
+			wantMethod: http.MethodPost,
+			wantHeader: map[string]string{"Authorization": "Bearer segredo", "Content-Type": "application/json", "X-Focus": "1"},
+		},
+		{
+			name:       "PUT com basic auth e content-type próprio",
+			req:        WebhookRequest{Method: http.MethodPut, Body: []byte("nivel=HIGH"), BasicUser: "piloto", BasicPassword: "torre", Headers: map[string]string{"Content-Type": "text/plain"}},
+			wantMethod: http.MethodPut,
+			wantHeader: map[string]string{"Authorization": "Basic cGlsb3RvOnRvcnJl", "Content-Type": "text/plain"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *http.Request
+			var body []byte
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r
+				body, _ = io.ReadAll(r.Body)
+			}))
+			defer srv.Close()
+
+			tt.req.URL = srv.URL + "/hook"
+			if err := SendWebhook(context.Background(), tt.req); err != nil {
+				t.Fatal(err)
+			}
+			if got.Method != tt.wantMethod || got.URL.Path != "/hook" {
+				t.Errorf("requisição %s %s, esperado %s /hook", got.Method, got.URL.Path, tt.wantMethod)
+			}
+			for key, want := range tt.wantHeader {
+				if v := got.Header.Get(key); v != want {
+					t.Errorf("cabeçalho %s = %q, esperado %q", key, v, want)
+				}
+			}
+			if string(body) != string(tt.req.Body) {
+				t.Errorf("corpo = %q, esperado %q", body, tt.req.Body)
+			}
+		})
+	}
+}
+
+func TestSendWebhookRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int // status de cada tentativa; a última se repete
+		retries      int
+		wantErr      string
+		wantAttempts int32
+	}{
+		{"sucesso após 503", []int{503, 503, 200}, 3, "", 3},
+		{"429 também é repetido", []int{429, 204}, 1, "", 2},
+		{"tentativas esgotadas", []int{500}, 2, "500", 3},
+		{"4xx não é repetido", []int{404}, 3, "404", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := int(attempts.Add(1)) - 1
+				w.WriteHeader(tt.statuses[min(n, len(tt.statuses)-1)])
+				io.WriteString(w, "resposta do servidor")
+			}))
+			defer srv.Close()
+
+			err := SendWebhook(context.Background(), WebhookRequest{URL: srv.URL, Retries: tt.retries, RetryDelay: time.Millisecond})
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("erro inesperado: %v", err)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Fatalf("erro = %v, esperado contendo %q", err, tt.wantErr)
+			case tt.wantErr != "" && !strings.Contains(err.Error(), "resposta do servidor"):
+				t.Errorf("erro sem o corpo da resposta: %v", err)
+			}
+			if n := attempts.Load(); n != tt.wantAttempts {
+				t.Errorf("%d tentativas, esperado %d", n, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestSendWebhookTimeout(t *testing.T) {
+	var attempts atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	start := time.Now()
+	err := SendWebhook(context.Background(), WebhookRequest{URL: srv.URL, Retries: 1, RetryDelay: time.Millisecond, Timeout: 50 * time.Millisecond})
+	if err == nil {
+		t.Fatal("esperado erro de timeout")
+	}
+	if n := attempts.Load(); n != 2 {
+		t.Errorf("%d tentativas, esperado 2: timeout conta como erro de rede", n)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("SendWebhook levou %v apesar do timeout de 50ms", elapsed)
+	}
+}
+
+func TestSendWebhookCanceledDuringRetry(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := SendWebhook(ctx, WebhookRequest{URL: srv.URL, Retries: 5, RetryDelay: time.Hour})
+	if err != context.DeadlineExceeded {
+		t.Fatalf("erro = %v, esperado %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestSendWebhookWithoutURL(t *testing.T) {
+	if err := SendWebhook(context.Background(), WebhookRequest{}); err == nil {
+		t.Fatal("esperado erro sem URL")
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"time"
+
+	"github.com/brutalzinn/focus-helper/config"
+)
+
+// limiterDecision é o destino de uma interrupção avaliada pelo limitador.
+type limiterDecision int
+
+const (
+	admitInterruption     limiterDecision = iota
+	mergeInterruption                     // agrupada a um disparo recente do mesmo nível ou ao alerta ativo
+	suppressInterruption                  // adiada: acima do limite por hora; quem dispara tenta de novo depois
+	supersedeInterruption                 // substitui o alerta ativo de nível mais baixo
+)
+
+// delivered indica se a interrupção chegou ao usuário, sozinha ou agrupada a
+// outra, e não precisa ser disparada de novo.
+func (d limiterDecision) delivered() bool {
+	return d != suppressInterruption
+}
+
+// activeAlert é o alerta cujas ações ainda estão em andamento.
+type activeAlert struct {
+	id       uint64
+	level    string
+	severity int
+}
+
+// interruptionLimiter limita quantas interrupções chegam ao usuário por hora,
+// juntando as que colidem. Não é seguro para uso concorrente; o alertManager
+// o protege com seu mutex.
+type interruptionLimiter struct {
+	cfg     config.RateLimitConfig
+	history []time.Time          // interrupções admitidas na última hora
+	last    map[string]time.Time // último disparo de cada nível
+}
+
+func newInterruptionLimiter(cfg config.RateLimitConfig) *interruptionLimiter {
+	return &interruptionLimiter{cfg: cfg, last: make(map[string]time.Time)}
+}
+
+// admit decide se uma interrupção do nível pode acontecer agora, dado o
+// alerta ativo, e a registra quando admitida. reason explica os agrupamentos
+// e as supressões.
+func (l *interruptionLimiter) admit(level string, severity int, active activeAlert, now time.Time) (decision limiterDecision, reason string) {
+	if !l.cfg.Enabled {
+		return admitInterruption, ""
+	}
+	cutoff := now.Add(-time.Hour)
+	for len(l.history) > 0 && l.history[0].Before(cutoff) {
+		l.history = l.history[1:]
+	}
+
+	if last, ok := l.last[level]; ok && now.Sub(last) < l.cfg.MergeWindow {
+		return mergeInterruption, "duplicado de um disparo recente"
+	}
+	if active.level != "" {
+		if severity <= active.severity {
+			return mergeInterruption, "agrupado ao alerta " + active.level + " em andamento"
+		}
+		l.last[level] = now
+		l.history = append(l.history, now)
+		return supersedeInterruption, ""
+	}
+	if l.cfg.MaxPerHour > 0 && len(l.history) >= l.cfg.MaxPerHour && severity < config.SeverityCritical {
+		return suppressInterruption, "limite de interrupções por hora atingido"
+	}
+	l.last[level] = now
+	l.history = append(l.history, now)
+	return admitInterruption, ""
+}
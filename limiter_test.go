@@ -0,0 +1,71 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/brutalzinn/focus-helper/config"
+)
+
+func TestInterruptionLimiter(t *testing.T) {
+	now := time.Date(2026, 10, 19, 14, 0, 0, 0, time.UTC)
+	l := newInterruptionLimiter(config.RateLimitConfig{Enabled: true, MaxPerHour: 2, MergeWindow: time.Minute})
+	low := activeAlert{id: 1, level: "LOW", severity: config.SeverityLow}
+	steps := []struct {
+		name     string
+		level    string
+		severity int
+		active   activeAlert
+		at       time.Duration
+		want     limiterDecision
+	}{
+		{"primeiro alerta", "LOW", config.SeverityLow, activeAlert{}, 0, admitInterruption},
+		{"duplicado na janela", "LOW", config.SeverityLow, activeAlert{}, 30 * time.Second, mergeInterruption},
+		{"abaixo do alerta ativo", "LOW", config.SeverityLow, activeAlert{id: 1, level: "HIGH", severity: config.SeverityHigh}, 2 * time.Minute, mergeInterruption},
+		{"substitui o alerta ativo", "HIGH", config.SeverityHigh, low, 3 * time.Minute, supersedeInterruption},
+		// A substituição conta no limite por hora.
+		{"limite atingido", "MEDIUM", config.SeverityMedium, activeAlert{}, 4 * time.Minute, suppressInterruption},
+		{"crítico passa do limite", "CRITICAL", config.SeverityCritical, activeAlert{}, 5 * time.Minute, admitInterruption},
+		{"limite liberado depois de uma hora", "MEDIUM", config.SeverityMedium, activeAlert{}, 63*time.Minute + time.Second, admitInterruption},
+	}
+	for _, step := range steps {
+		if got, reason := l.admit(step.level, step.severity, step.active, now.Add(step.at)); got != step.want {
+			t.Errorf("%s: decisão %v (%s), esperado %v", step.name, got, reason, step.want)
+		}
+	}
+}
+
+func TestLevelSeverity(t *testing.T) {
+	tests := []struct {
+		level config.AlertLevel
+		want  int
+	}{
+		{config.AlertLevel{Level: "HYPERFOCUS_CRITICAL"}, config.SeverityCritical},
+		{config.AlertLevel{Level: "DAILY_HIGH"}, config.SeverityHigh},
+		{config.AlertLevel{Level: "LOW"}, config.SeverityLow},
+		{config.AlertLevel{Level: "POMODORO_BREAK"}, config.SeverityMedium},
+		{config.AlertLevel{Level: "BURNOUT", Severity: config.SeverityCritical}, config.SeverityCritical},
+		{config.AlertLevel{Level: "LOW", Severity: config.SeverityHigh}, config.SeverityHigh},
+	}
+	for _, tt := range tests {
+		if got := config.LevelSeverity(tt.level); got != tt.want {
+			t.Errorf("LevelSeverity(%+v) = %d, esperado %d", tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestRepeatCapRequiresRateLimit(t *testing.T) {
+	tests := []struct {
+		cfg  config.RateLimitConfig
+		want int
+	}{
+		{config.RateLimitConfig{Enabled: true, MaxRepetitions: 2}, 2},
+		{config.RateLimitConfig{Enabled: false, MaxRepetitions: 2}, 0},
+		{config.RateLimitConfig{Enabled: true}, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.cfg.RepeatCap(); got != tt.want {
+			t.Errorf("RepeatCap(%+v) = %d, esperado %d", tt.cfg, got, tt.want)
+		}
+	}
+}
@@ -1,12 +1,18 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"flag"
+	"fmt"
 	"io"
 	"log"
 	"math/rand"
 	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/brutalzinn/focus-helper/actions"
@@ -16,24 +22,53 @@ import (
 	"github.com/brutalzinn/focus-helper/database"
 	"github.com/brutalzinn/focus-helper/integrations"
 	"github.com/brutalzinn/focus-helper/notifications"
+	"github.com/brutalzinn/focus-helper/plugins"
 )
 
 var appConfig config.Config
 var db *sql.DB
 var activityMonitor *activity.Monitor
 var atcPromptManager *integrations.PromptManager
+var alerts *alertManager
+var stateReport *stateReporter
 
 type AppState struct {
 	lastActivityTime         time.Time
 	continuousUsageStartTime time.Time
-	warnedThresholds         map[time.Duration]bool
+	sessionStartTime         time.Time // início real da sessão, sem o desconto das pausas insuficientes
+	warnedThresholds         map[string]bool
+	preWarned                map[string]bool
+	idle                     bool
 	currentHyperfocusState   *config.HyperfocusState
+	budget                   *dailyBudget
+	flightPlan               *database.FlightPlan
+	planStart                time.Time // início do plano de voo ativo, base dos limites relativos a ele
+	planCache                flightPlanCache
+	enforcedBreak            atomic.Int64 // pausa mínima exigida por um bloqueio de tela, em nanossegundos
 }
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "flight-plan":
+			runFlightPlanCommand(os.Args[2:])
+			return
+		case "status":
+			runStatusCommand(os.Args[2:])
+			return
+		case "test-alert":
+			runTestAlertCommand(os.Args[2:])
+			return
+		}
+	}
 	debugFlag := flag.Bool("debug", false, "Set to true to enable debug mode")
+	pomodoroFlag := flag.Bool("pomodoro", false, "Run fixed work/break intervals instead of hyperfocus detection")
 	flag.Parse()
 	appConfig = config.Init(*debugFlag)
+	if *pomodoroFlag {
+		config.AppConfig.Pomodoro.Enabled = true
+		appConfig = config.AppConfig
+	}
 	setupLogger()
 
 	log.Println("--- Iniciando o Focus Helper ---")
@@ -48,27 +83,74 @@ func main() {
 	}
 	defer db.Close()
 
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	alerts = newAlertManager(ctx)
+	// Retoma processos que ficaram suspensos se a execução anterior terminou num crash.
+	integrations.ResumeAllSuspended(appConfig.SuspendedAppsFile)
+	defer integrations.ResumeAllSuspended(appConfig.SuspendedAppsFile)
+
+	// Restaura volumes que ficaram reduzidos se a execução anterior terminou num crash.
+	audio.RestoreDuckedStreams()
 	audio.InitSpeaker()
+	audio.CleanStaleRenders()
 	activityMonitor = activity.NewMonitor()
 	atcPromptManager = integrations.NewATCPromptManager()
 
 	state := &AppState{
 		lastActivityTime:         time.Now(),
 		continuousUsageStartTime: time.Now(),
-		warnedThresholds:         make(map[time.Duration]bool),
+		sessionStartTime:         time.Now(),
+		warnedThresholds:         make(map[string]bool),
+		preWarned:                make(map[string]bool),
 		currentHyperfocusState:   nil,
 	}
+	if appConfig.DailyBudget.Enabled {
+		state.budget = loadDailyBudget(time.Now())
+	}
+	alerts.requireBreak = state.requireBreak
+	stateReport = startStateReporter(ctx, appConfig.MQTT)
+	// loops aguarda os loops que usam o banco antes de fechá-lo.
+	var loops sync.WaitGroup
+	plugins.Start(ctx, appConfig.PluginDirs)
 
-	go monitorActivityLoop(state)
+	loops.Add(1)
+	if appConfig.Pomodoro.Enabled {
+		log.Println("Modo pomodoro habilitado.")
+		go func() {
+			defer loops.Done()
+			pomodoroLoop(ctx)
+		}()
+	} else {
+		go func() {
+			defer loops.Done()
+			monitorActivityLoop(ctx, state)
+		}()
+	}
+	if appConfig.DailyDigest.Enabled {
+		loops.Add(1)
+		go func() {
+			defer loops.Done()
+			digestLoop(ctx)
+		}()
+	}
 	if appConfig.WellbeingQuestionsEnabled {
 		go schedulerLoop()
 	} else {
 		log.Println("Questões de bem estar desativadas.")
 	}
 
-	audio.PlayRadioSimulation("Bem-vindo ao Focus Helper. Estamos prontos para ajudar você a manter o foco e o bem-estar.", 1.0, 0.5, "radio_static.wav")
+	if appConfig.RenderCache.Enabled {
+		go actions.PreRender(ctx, allAlertLevels())
+	}
+	audio.PlayRadioSimulation(ctx, "Bem-vindo ao Focus Helper. Estamos prontos para ajudar você a manter o foco e o bem-estar.", 1.0, 0.5, "radio_static.wav")
 	log.Println("Focus Helper está rodando em background.")
-	select {}
+	<-ctx.Done()
+	log.Println("--- Encerrando o Focus Helper ---")
+	loops.Wait()
+	stateReport.wait()
+	integrations.CloseMQTT()
+	plugins.Wait()
 }
 
 func setupLogger() {
@@ -80,34 +162,71 @@ func setupLogger() {
 	log.SetOutput(multiWriter)
 }
 
-func monitorActivityLoop(state *AppState) {
+func monitorActivityLoop(ctx context.Context, state *AppState) {
 	ticker := time.NewTicker(config.AppConfig.ActivityCheckRate)
 	defer ticker.Stop()
-	for range ticker.C {
+	for {
+		select {
+		case <-ctx.Done():
+			// Sem isso a sessão em andamento nunca entraria no histórico.
+			logSession(state)
+			if state.budget != nil {
+				state.budget.save()
+			}
+			return
+		case <-ticker.C:
+		}
 		isIdle := time.Since(state.lastActivityTime) > config.AppConfig.IdleTimeout
 		if activityMonitor.HasActivity() {
 			if isIdle {
-				resetState(state)
+				endBreak(state)
 			}
 			state.lastActivityTime = time.Now()
 		}
 		if isIdle {
+			if state.budget != nil {
+				state.budget.pause()
+			}
+			if !state.idle {
+				state.idle = true
+				alerts.cancelAll(actions.ErrUserIdle)
+			}
+			writeStatus(state, true)
 			continue
 		}
+		state.idle = false
+		if state.budget != nil {
+			state.budget.track(time.Now())
+		}
+		refreshFlightPlan(state)
 		usageDuration := time.Since(state.continuousUsageStartTime)
-		for _, level := range config.AppConfig.AlertLevels {
-			if level.Enabled && usageDuration >= level.Threshold && !state.warnedThresholds[level.Threshold] {
-				log.Printf("Alerta de hiperfoco acionado: %s (duração: %v)", level.Level, usageDuration)
-				if state.currentHyperfocusState == nil || state.currentHyperfocusState.Level != level.Level {
-					state.currentHyperfocusState = &config.HyperfocusState{
-						Level:     level.Level,
-						StartTime: time.Now(),
-					}
-				}
-				go actions.Execute(level, state.currentHyperfocusState)
-				state.warnedThresholds[level.Threshold] = true
+		checkThresholds(state, usageDuration)
+		checkPreAlerts(state, usageDuration)
+		writeStatus(state, false)
+	}
+}
+
+// checkThresholds dispara os níveis cujo limite foi atingido. Um nível adiado
+// pelo limitador não é marcado e volta a ser tentado na próxima verificação.
+func checkThresholds(state *AppState, usage time.Duration) {
+	for _, level := range config.AppConfig.AlertLevels {
+		if !level.Enabled || usage < levelThreshold(state, level) || state.warnedThresholds[level.Level] {
+			continue
+		}
+		hyperfocus := state.currentHyperfocusState
+		if hyperfocus == nil || hyperfocus.Level != level.Level {
+			hyperfocus = &config.HyperfocusState{
+				Level:     level.Level,
+				StartTime: time.Now(),
+				Task:      flightPlanTask(state),
 			}
 		}
+		if !alerts.fire(level, hyperfocus, usage).delivered() {
+			continue
+		}
+		log.Printf("Alerta de hiperfoco acionado: %s (duração: %v)", level.Level, usage)
+		state.currentHyperfocusState = hyperfocus
+		state.warnedThresholds[level.Level] = true
 	}
 }
 
@@ -125,14 +244,17 @@ func schedulerLoop() {
 }
 
 func askWellbeingQuestion() {
+	if !alerts.admitWellbeingQuestion() {
+		return
+	}
 	go func() {
 		finalPrompt := atcPromptManager.FormatPrompt("Como você está se sentindo agora? Você gostaria de fazer uma pausa para o bem-estar?")
-		questionText, err := integrations.GenerateTextWithLlama(config.AppConfig.Llama.Model, finalPrompt)
+		questionText, err := integrations.GenerateTextWithLlama(alerts.root, config.AppConfig.Llama.Model, finalPrompt)
 		if err != nil {
 			log.Printf("Erro ao gerar pergunta com Llama, usando fallback: %v", err)
 			questionText = "Que tal uma pausa para um copo d'água?"
 		}
-		audio.PlayRadioSimulation(questionText, 1, 1, "radio_static.wav")
+		audio.PlayRadioSimulation(alerts.root, questionText, 1, 1, "radio_static.wav")
 		answeredYes := notifications.ShowQuestionPopup("Pausa para o Bem-estar", questionText)
 		answer := "Não"
 		if answeredYes {
@@ -142,20 +264,122 @@ func askWellbeingQuestion() {
 	}()
 }
 
+// endBreak aplica a política de pausas quando o usuário retorna da ociosidade.
+func endBreak(state *AppState) {
+	session := state.lastActivityTime.Sub(state.continuousUsageStartTime)
+	policy := config.AppConfig.BreakPolicy
+	if enforced := time.Duration(state.enforcedBreak.Load()); enforced > policy.MinBreak {
+		policy.MinBreak = enforced
+		if policy.MaxBreak > 0 && policy.MaxBreak < enforced {
+			policy.MaxBreak = enforced
+		}
+	}
+	result := activity.EvaluateBreak(session, time.Since(state.lastActivityTime), policy)
+	if result.Sufficient {
+		state.enforcedBreak.Store(0)
+		resetState(state)
+		alerts.confirmBreak()
+	} else {
+		coolDownState(state, result)
+	}
+	go announceReturn(result)
+}
+
+// requireBreak registra uma pausa mínima imposta por uma ação; a próxima pausa
+// só zera a sessão se durar pelo menos esse tempo.
+func (state *AppState) requireBreak(d time.Duration) {
+	for {
+		current := state.enforcedBreak.Load()
+		if int64(d) <= current || state.enforcedBreak.CompareAndSwap(current, int64(d)) {
+			log.Printf("Pausa mínima exigida: %v.", d.Round(time.Second))
+			return
+		}
+	}
+}
+
 func resetState(state *AppState) {
 	log.Println("Usuário retornou da ociosidade. Reiniciando contadores.")
+	logSession(state)
 
 	now := time.Now()
 	state.continuousUsageStartTime = now
+	state.sessionStartTime = now
 	state.lastActivityTime = now
-	state.warnedThresholds = make(map[time.Duration]bool)
+	state.warnedThresholds = make(map[string]bool)
+	state.preWarned = make(map[string]bool)
 	state.currentHyperfocusState = nil
+	if state.flightPlan != nil {
+		log.Printf("Plano de voo %q encerrado pela pausa.", state.flightPlan.Task)
+		if err := database.CloseFlightPlan(db); err != nil {
+			log.Printf("Erro ao encerrar plano de voo: %v", err)
+		}
+		state.flightPlan = nil
+	}
+}
+
+// logSession registra no histórico a sessão encerrada pela pausa ou pelo
+// encerramento do Focus Helper.
+func logSession(state *AppState) {
+	session := database.Session{
+		StartedAt: state.sessionStartTime,
+		EndedAt:   state.lastActivityTime,
+		Focus:     state.lastActivityTime.Sub(state.continuousUsageStartTime),
+		Task:      flightPlanTask(state),
+	}
+	if session.Focus <= 0 {
+		return
+	}
+	if state.currentHyperfocusState != nil {
+		session.Level = state.currentHyperfocusState.Level
+	}
+	database.LogSession(db, session)
+}
+
+// coolDownState reduz o uso acumulado após uma pausa insuficiente, liberando
+// os alertas cujos limites voltaram a ficar acima do uso restante.
+func coolDownState(state *AppState, result activity.BreakResult) {
+	log.Printf("Pausa insuficiente (%v de %v). Uso acumulado reduzido de %v para %v.",
+		result.Taken.Round(time.Second), result.Required.Round(time.Second),
+		result.Session.Round(time.Second), result.Remaining)
+
+	now := time.Now()
+	state.continuousUsageStartTime = now.Add(-result.Remaining)
+	state.lastActivityTime = now
+	for _, level := range config.AppConfig.AlertLevels {
+		if levelThreshold(state, level) > result.Remaining {
+			delete(state.warnedThresholds, level.Level)
+			delete(state.preWarned, level.Level)
+		}
+	}
+	if len(state.warnedThresholds) == 0 {
+		state.currentHyperfocusState = nil
+	}
+}
+
+func announceReturn(result activity.BreakResult) {
+	var instruction, fallback string
+	if result.Sufficient {
+		instruction = fmt.Sprintf("Informe ao Alfa-Um que ele retornou de uma pausa de %s, suficiente para a sessão de %s, e que seus contadores foram reiniciados.",
+			formatMinutes(result.Taken), formatMinutes(result.Session))
+		fallback = "Alfa-Um, Torre. Pausa concluída, contadores reiniciados."
+	} else {
+		instruction = fmt.Sprintf("Informe ao Alfa-Um que a pausa de %s foi insuficiente, pois a sessão de %s exige %s de pausa. O tempo de foco acumulado foi reduzido para %s.",
+			formatMinutes(result.Taken), formatMinutes(result.Session), formatMinutes(result.Required), formatMinutes(result.Remaining))
+		fallback = "Alfa-Um, Torre. Pausa insuficiente, contadores mantidos parcialmente."
+	}
 	prompt := integrations.NewATCPromptManager()
-	text := prompt.FormatPrompt("Informe ao Alfa-Um que ele retornou da ociosidade e que seus contadores foram reiniciados.")
-	response, err := integrations.GenerateTextWithLlama(config.AppConfig.Llama.Model, text)
+	text := prompt.FormatPrompt(instruction)
+	response, err := integrations.GenerateTextWithLlama(alerts.root, config.AppConfig.Llama.Model, text)
 	if err != nil {
 		log.Printf("Erro ao gerar resposta com Llama: %v", err)
-		response = "Usuário ativo novamente."
+		response = fallback
+	}
+	audio.PlayRadioSimulation(alerts.root, response, 1.0, 0.5, "radio_static.wav")
+}
+
+func formatMinutes(d time.Duration) string {
+	if d < time.Minute {
+		return fmt.Sprintf("%d segundos", int(d.Seconds()))
 	}
-	go audio.PlayRadioSimulation(response, 1.0, 0.5, "radio_static.wav")
+	return fmt.Sprintf("%d minutos", int(d.Minutes()))
 }
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/brutalzinn/focus-helper/config"
+	"github.com/brutalzinn/focus-helper/database"
+)
+
+func TestMonitorActivityLoopLogsSessionOnShutdown(t *testing.T) {
+	openTestDB(t)
+	config.AppConfig.ActivityCheckRate = time.Hour
+
+	now := time.Now()
+	state := &AppState{
+		sessionStartTime:         now.Add(-45 * time.Minute),
+		continuousUsageStartTime: now.Add(-30 * time.Minute),
+		lastActivityTime:         now,
+		currentHyperfocusState:   &config.HyperfocusState{Level: "HYPERFOCUS_LOW"},
+		flightPlan:               &database.FlightPlan{Task: "relatório"},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	monitorActivityLoop(ctx, state)
+
+	sessions, err := database.GetSessions(db, now.Add(-time.Hour), now.Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 1 {
+		t.Fatalf("%d sessões registradas no encerramento, esperado 1", len(sessions))
+	}
+	got := sessions[0]
+	if got.Focus != 30*time.Minute || got.Level != "HYPERFOCUS_LOW" || got.Task != "relatório" ||
+		!got.StartedAt.Equal(state.sessionStartTime) || !got.EndedAt.Equal(state.lastActivityTime) {
+		t.Errorf("sessão registrada = %+v", got)
+	}
+}
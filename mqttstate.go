@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/brutalzinn/focus-helper/config"
+	"github.com/brutalzinn/focus-helper/integrations"
+	"github.com/brutalzinn/focus-helper/status"
+)
+
+// mqttState é o payload retido no tópico focus-helper/<host>/state.
+type mqttState struct {
+	Online         bool      `json:"online"`
+	Level          string    `json:"level"`
+	SessionSeconds int64     `json:"session_seconds"`
+	Paused         bool      `json:"paused"`
+	Task           string    `json:"task,omitempty"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// stateReporter publica o status do daemon no tópico de estado sem bloquear o
+// loop de monitoramento; se o broker estiver lento, só o status mais recente é enviado.
+type stateReporter struct {
+	broker  integrations.MQTTBroker
+	topic   string
+	qos     byte
+	updates chan status.Status
+	stopped chan struct{}
+}
+
+// startStateReporter retorna nil quando o tópico de estado está desabilitado.
+func startStateReporter(ctx context.Context, cfg config.MQTTConfig) *stateReporter {
+	if !cfg.Enabled {
+		return nil
+	}
+	cfg = cfg.WithDefaults()
+	offline, _ := json.Marshal(mqttState{Online: false})
+	r := &stateReporter{
+		broker: integrations.MQTTBroker{
+			URL:      cfg.Broker,
+			ClientID: cfg.ClientID + "-state",
+			Username: cfg.Username,
+			Password: cfg.Password,
+			Will:     &integrations.MQTTMessage{Topic: cfg.StateTopic, Payload: offline, QoS: cfg.StateQoS, Retain: true},
+		},
+		topic:   cfg.StateTopic,
+		qos:     cfg.StateQoS,
+		updates: make(chan status.Status, 1),
+		stopped: make(chan struct{}),
+	}
+	log.Printf("Publicando estado no tópico MQTT %s.", r.topic)
+	go r.run(ctx)
+	return r
+}
+
+// report agenda a publicação do status, descartando um status ainda não enviado.
+func (r *stateReporter) report(st status.Status) {
+	if r == nil {
+		return
+	}
+	select {
+	case <-r.updates:
+	default:
+	}
+	r.updates <- st
+}
+
+// wait aguarda a publicação do estado offline no encerramento.
+func (r *stateReporter) wait() {
+	if r == nil {
+		return
+	}
+	select {
+	case <-r.stopped:
+	case <-time.After(5 * time.Second):
+	}
+}
+
+func (r *stateReporter) run(ctx context.Context) {
+	defer close(r.stopped)
+	for {
+		select {
+		case <-ctx.Done():
+			publishCtx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+			r.publish(publishCtx, mqttState{Online: false, UpdatedAt: time.Now()})
+			cancel()
+			return
+		case st := <-r.updates:
+			publishCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+			r.publish(publishCtx, mqttState{
+				Online:         true,
+				Level:          st.Level,
+				SessionSeconds: st.SessionSeconds,
+				Paused:         st.Idle || st.Snoozed(st.UpdatedAt),
+				Task:           st.Task,
+				UpdatedAt:      st.UpdatedAt,
+			})
+			cancel()
+		}
+	}
+}
+
+func (r *stateReporter) publish(ctx context.Context, state mqttState) {
+	payload, err := json.Marshal(state)
+	if err != nil {
+		return
+	}
+	msg := integrations.MQTTMessage{Topic: r.topic, Payload: payload, QoS: r.qos, Retain: true}
+	if err := integrations.PublishMQTT(ctx, r.broker, msg); err != nil {
+		log.Printf("Erro ao publicar estado MQTT: %v", err)
+	}
+}
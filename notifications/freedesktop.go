@@ -0,0 +1,110 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	notificationsService = "org.freedesktop.Notifications"
+	notificationsPath    = "/org/freedesktop/Notifications"
+)
+
+// Urgência das notificações, conforme a especificação freedesktop.
+const (
+	UrgencyLow      byte = 0
+	UrgencyNormal   byte = 1
+	UrgencyCritical byte = 2
+)
+
+// NotificationButton é um botão de ação da notificação.
+type NotificationButton struct {
+	Key   string // identificador devolvido quando o botão é pressionado
+	Label string
+}
+
+// Notification descreve uma notificação enviada ao servidor de notificações.
+type Notification struct {
+	BusAddress string // barramento de sessão a usar (padrão: DBUS_SESSION_BUS_ADDRESS)
+	AppName    string
+	Title      string
+	Body       string
+	Urgency    byte
+	Buttons    []NotificationButton
+	Timeout    time.Duration // 0 deixa a expiração a cargo do servidor
+}
+
+// Notify envia a notificação pelo org.freedesktop.Notifications e aguarda o
+// usuário pressionar um botão, fechar a notificação ou o contexto ser
+// cancelado. Retorna a Key do botão pressionado, ou vazio.
+func Notify(ctx context.Context, n Notification) (string, error) {
+	conn, err := connectSessionBus(n.BusAddress)
+	if err != nil {
+		return "", fmt.Errorf("erro ao conectar ao D-Bus de sessão: %w", err)
+	}
+	defer conn.Close()
+
+	// Assina os sinais antes de enviar a notificação para não perder uma resposta rápida.
+	if err := conn.AddMatchSignalContext(ctx,
+		dbus.WithMatchObjectPath(notificationsPath),
+		dbus.WithMatchInterface(notificationsService),
+	); err != nil {
+		return "", fmt.Errorf("erro ao assinar sinais de notificação: %w", err)
+	}
+	signals := make(chan *dbus.Signal, 10)
+	conn.Signal(signals)
+
+	actions := make([]string, 0, 2*len(n.Buttons))
+	for _, button := range n.Buttons {
+		actions = append(actions, button.Key, button.Label)
+	}
+	hints := map[string]dbus.Variant{"urgency": dbus.MakeVariant(n.Urgency)}
+	timeout := int32(-1)
+	if n.Timeout > 0 {
+		timeout = int32(n.Timeout.Milliseconds())
+	}
+
+	var id uint32
+	obj := conn.Object(notificationsService, notificationsPath)
+	err = obj.CallWithContext(ctx, notificationsService+".Notify", 0,
+		n.AppName, uint32(0), "", n.Title, n.Body, actions, hints, timeout).Store(&id)
+	if err != nil {
+		return "", fmt.Errorf("Notify: %w", err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Remove a notificação que perdeu o sentido; o contexto já foi cancelado.
+			obj.Call(notificationsService+".CloseNotification", 0, id)
+			return "", ctx.Err()
+		case signal, ok := <-signals:
+			if !ok {
+				return "", fmt.Errorf("conexão com o D-Bus encerrada")
+			}
+			if len(signal.Body) < 2 {
+				continue
+			}
+			if signalID, _ := signal.Body[0].(uint32); signalID != id {
+				continue
+			}
+			switch signal.Name {
+			case notificationsService + ".ActionInvoked":
+				key, _ := signal.Body[1].(string)
+				return key, nil
+			case notificationsService + ".NotificationClosed":
+				return "", nil
+			}
+		}
+	}
+}
+
+func connectSessionBus(address string) (*dbus.Conn, error) {
+	if address == "" {
+		return dbus.ConnectSessionBus()
+	}
+	return dbus.Connect(address)
+}
@@ -15,7 +15,19 @@ func ShowQuestionPopup(title, question string) bool {
 	return dialog.Message("%s", question).Title(title).YesNo()
 }
 
+// ShowAcknowledgePopup exibe o alerta com a pergunta se o usuário vai fazer a
+// pausa. Retorna true só quando ele confirma; fechar o diálogo retorna false.
+func ShowAcknowledgePopup(title, message string) bool {
+	return dialog.Message("%s\n\nVai fazer uma pausa agora?", message).Title(title).YesNo()
+}
+
 // ShowDesktopNotification envia uma notificação padrão de sistema.
 func ShowDesktopNotification(title, message string) {
 	beeep.Alert(title, message, "") // O último argumento é o ícone, opcional.
 }
+
+// ShowQuietNotification envia uma notificação de sistema sem som, para avisos
+// que não devem interromper.
+func ShowQuietNotification(title, message string) {
+	beeep.Notify(title, message, "")
+}
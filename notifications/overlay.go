@@ -0,0 +1,281 @@
+package notifications
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jezek/xgb"
+	"github.com/jezek/xgb/xproto"
+)
+
+// BreakOverlay descreve a janela de pausa em tela cheia.
+type BreakOverlay struct {
+	Title         string
+	Message       string
+	BreakDuration time.Duration
+	ConfirmPhrase string // frase que o usuário digita para sair sem fazer a pausa
+}
+
+const (
+	keysymBackspace = 0xff08
+	keysymReturn    = 0xff0d
+	keysymEscape    = 0xff1b
+
+	// fallbackCharWidth é usado quando a fonte não informa a largura máxima.
+	fallbackCharWidth = 10
+)
+
+// ShowBreakOverlay abre uma janela X11 em tela cheia com a mensagem e a
+// contagem regressiva da pausa. Bloqueia até closeCh ser fechado ou até o
+// usuário digitar a frase de confirmação; nesse caso retorna escaped = true.
+func ShowBreakOverlay(overlay BreakOverlay, closeCh <-chan struct{}) (escaped bool, err error) {
+	conn, err := xgb.NewConn()
+	if err != nil {
+		return false, fmt.Errorf("erro ao conectar ao X11: %w", err)
+	}
+	defer conn.Close()
+
+	w, err := newOverlayWindow(conn)
+	if err != nil {
+		return false, err
+	}
+	defer xproto.DestroyWindow(conn, w.window)
+
+	done := make(chan struct{})
+	defer close(done)
+	events := pumpEvents(conn.WaitForEvent, done)
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	end := time.Now().Add(overlay.BreakDuration)
+	typed := ""
+	w.draw(overlay, time.Until(end), typed)
+	for {
+		select {
+		case <-closeCh:
+			return false, nil
+		case <-ticker.C:
+			w.draw(overlay, time.Until(end), typed)
+		case ev, ok := <-events:
+			if !ok {
+				return false, fmt.Errorf("conexão com o X11 encerrada")
+			}
+			switch e := ev.(type) {
+			case xproto.ExposeEvent:
+				w.draw(overlay, time.Until(end), typed)
+			case xproto.KeyPressEvent:
+				typed = w.handleKey(e, typed)
+				if overlay.ConfirmPhrase != "" && strings.EqualFold(strings.TrimSpace(typed), overlay.ConfirmPhrase) {
+					return true, nil
+				}
+				w.draw(overlay, time.Until(end), typed)
+			}
+		}
+	}
+}
+
+// pumpEvents repassa os eventos de wait até a conexão fechar ou done ser
+// fechado; sem done a goroutine ficaria presa no envio depois que a janela fecha.
+func pumpEvents(wait func() (xgb.Event, xgb.Error), done <-chan struct{}) <-chan xgb.Event {
+	events := make(chan xgb.Event)
+	go func() {
+		defer close(events)
+		for {
+			ev, xerr := wait()
+			if ev == nil && xerr == nil {
+				return
+			}
+			if ev == nil {
+				continue
+			}
+			select {
+			case events <- ev:
+			case <-done:
+				return
+			}
+		}
+	}()
+	return events
+}
+
+type overlayWindow struct {
+	conn          *xgb.Conn
+	window        xproto.Window
+	gc            xproto.Gcontext
+	width, height int
+	charWidth     int
+	lineHeight    int
+	ascent        int
+	minKeycode    xproto.Keycode
+	keysyms       []xproto.Keysym
+	perKeycode    int
+}
+
+func newOverlayWindow(conn *xgb.Conn) (*overlayWindow, error) {
+	setup := xproto.Setup(conn)
+	screen := setup.DefaultScreen(conn)
+	w := &overlayWindow{
+		conn:   conn,
+		width:  int(screen.WidthInPixels),
+		height: int(screen.HeightInPixels),
+	}
+
+	var err error
+	if w.window, err = xproto.NewWindowId(conn); err != nil {
+		return nil, err
+	}
+	// override-redirect cobre a tela inteira sem passar pelo gerenciador de janelas.
+	err = xproto.CreateWindowChecked(conn, screen.RootDepth, w.window, screen.Root,
+		0, 0, screen.WidthInPixels, screen.HeightInPixels, 0,
+		xproto.WindowClassInputOutput, screen.RootVisual,
+		xproto.CwBackPixel|xproto.CwOverrideRedirect|xproto.CwEventMask,
+		[]uint32{screen.BlackPixel, 1, xproto.EventMaskExposure | xproto.EventMaskKeyPress}).Check()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar janela de pausa: %w", err)
+	}
+
+	font, err := openOverlayFont(conn)
+	if err != nil {
+		return nil, err
+	}
+	fontInfo, err := xproto.QueryFont(conn, xproto.Fontable(font)).Reply()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao consultar fonte: %w", err)
+	}
+	w.charWidth = int(fontInfo.MaxBounds.CharacterWidth)
+	if w.charWidth <= 0 {
+		w.charWidth = fallbackCharWidth
+	}
+	w.ascent = int(fontInfo.FontAscent)
+	w.lineHeight = int(fontInfo.FontAscent+fontInfo.FontDescent) + 8
+
+	if w.gc, err = xproto.NewGcontextId(conn); err != nil {
+		return nil, err
+	}
+	xproto.CreateGC(conn, w.gc, xproto.Drawable(w.window),
+		xproto.GcForeground|xproto.GcBackground|xproto.GcFont,
+		[]uint32{screen.WhitePixel, screen.BlackPixel, uint32(font)})
+
+	count := byte(setup.MaxKeycode - setup.MinKeycode + 1)
+	mapping, err := xproto.GetKeyboardMapping(conn, setup.MinKeycode, count).Reply()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler mapa de teclado: %w", err)
+	}
+	w.minKeycode = setup.MinKeycode
+	w.keysyms = mapping.Keysyms
+	w.perKeycode = int(mapping.KeysymsPerKeycode)
+
+	xproto.MapWindow(conn, w.window)
+	// O teclado só pode ser capturado depois que a janela fica visível.
+	for i := 0; i < 10; i++ {
+		reply, err := xproto.GrabKeyboard(conn, true, w.window, xproto.TimeCurrentTime,
+			xproto.GrabModeAsync, xproto.GrabModeAsync).Reply()
+		if err == nil && reply.Status == xproto.GrabStatusSuccess {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return w, nil
+}
+
+func openOverlayFont(conn *xgb.Conn) (xproto.Font, error) {
+	font, err := xproto.NewFontId(conn)
+	if err != nil {
+		return 0, err
+	}
+	for _, name := range []string{"-misc-fixed-bold-r-normal--18-*-*-*-*-*-iso8859-1", "10x20", "fixed"} {
+		if xproto.OpenFontChecked(conn, font, uint16(len(name)), name).Check() == nil {
+			return font, nil
+		}
+	}
+	return 0, fmt.Errorf("nenhuma fonte X11 disponível")
+}
+
+func (w *overlayWindow) draw(overlay BreakOverlay, remaining time.Duration, typed string) {
+	lines := []string{overlay.Title, ""}
+	lines = append(lines, wrapText(overlay.Message, w.width/w.charWidth-8)...)
+	lines = append(lines, "")
+	if remaining > 0 {
+		remaining = remaining.Round(time.Second)
+		lines = append(lines, fmt.Sprintf("Pausa: %02d:%02d restantes", int(remaining.Minutes()), int(remaining.Seconds())%60))
+	} else {
+		lines = append(lines, "Pausa concluída. A torre aguarda seu retorno.")
+	}
+	if overlay.ConfirmPhrase != "" {
+		lines = append(lines, "", fmt.Sprintf("Para sair sem pausa, digite: %s", overlay.ConfirmPhrase), "> "+typed)
+	}
+
+	xproto.ClearArea(w.conn, false, w.window, 0, 0, 0, 0)
+	y := (w.height - len(lines)*w.lineHeight) / 2
+	for _, line := range lines {
+		text := toLatin1(line)
+		if len(text) > 255 {
+			text = text[:255]
+		}
+		x := (w.width - len(text)*w.charWidth) / 2
+		if x < 0 {
+			x = 0
+		}
+		xproto.ImageText8(w.conn, byte(len(text)), xproto.Drawable(w.window), w.gc, int16(x), int16(y+w.ascent), text)
+		y += w.lineHeight
+	}
+}
+
+// handleKey converte a tecla pressionada e atualiza o texto digitado.
+func (w *overlayWindow) handleKey(e xproto.KeyPressEvent, typed string) string {
+	index := int(e.Detail-w.minKeycode) * w.perKeycode
+	if index < 0 || index >= len(w.keysyms) {
+		return typed
+	}
+	column := 0
+	if e.State&xproto.ModMaskShift != 0 && w.perKeycode > 1 {
+		column = 1
+	}
+	keysym := w.keysyms[index+column]
+	switch {
+	case keysym == keysymBackspace:
+		if runes := []rune(typed); len(runes) > 0 {
+			return string(runes[:len(runes)-1])
+		}
+	case keysym == keysymEscape || keysym == keysymReturn:
+		return ""
+	case keysym >= 0x20 && keysym <= 0xff:
+		return typed + string(rune(keysym))
+	}
+	return typed
+}
+
+// toLatin1 converte o texto para a codificação das fontes X11 principais.
+func toLatin1(text string) string {
+	out := make([]byte, 0, len(text))
+	for _, r := range text {
+		if r > 0xff {
+			r = '?'
+		}
+		out = append(out, byte(r))
+	}
+	return string(out)
+}
+
+func wrapText(text string, width int) []string {
+	if width <= 0 {
+		return []string{text}
+	}
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		if line != "" && len([]rune(line))+1+len([]rune(word)) > width {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
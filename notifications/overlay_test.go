@@ -0,0 +1,82 @@
+package notifications
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jezek/xgb"
+	"github.com/jezek/xgb/xproto"
+)
+
+func TestPumpEventsStopsWhenDone(t *testing.T) {
+	done := make(chan struct{})
+	events := pumpEvents(func() (xgb.Event, xgb.Error) {
+		return xproto.ExposeEvent{}, nil
+	}, done)
+	<-events
+	close(done)
+
+	// Sem leitor, a goroutine só termina se observar done.
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case _, ok := <-events:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatal("a leitura de eventos não terminou depois de done")
+		}
+	}
+}
+
+func TestPumpEventsClosesWithConnection(t *testing.T) {
+	calls := 0
+	events := pumpEvents(func() (xgb.Event, xgb.Error) {
+		calls++
+		switch calls {
+		case 1:
+			return xproto.KeyPressEvent{Detail: 38}, nil
+		case 2:
+			return nil, xproto.WindowError{}
+		}
+		return nil, nil
+	}, make(chan struct{}))
+
+	if ev, ok := <-events; !ok || ev.(xproto.KeyPressEvent).Detail != 38 {
+		t.Fatalf("primeiro evento = %v, %v", ev, ok)
+	}
+	select {
+	case ev, ok := <-events:
+		if ok {
+			t.Errorf("evento inesperado %v depois do erro", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("o canal não fechou com a conexão")
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	tests := []struct {
+		text  string
+		width int
+		want  []string
+	}{
+		{"faça uma pausa agora", 10, []string{"faça uma", "pausa", "agora"}},
+		{"faça uma pausa", 0, []string{"faça uma pausa"}},
+		{"faça uma pausa", -8, []string{"faça uma pausa"}},
+		{"", 10, nil},
+	}
+	for _, tt := range tests {
+		if got := wrapText(tt.text, tt.width); strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("wrapText(%q, %d) = %q, esperado %q", tt.text, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestToLatin1(t *testing.T) {
+	if got := toLatin1("Pausa concluída ✈"); got != "Pausa conclu\xedda ?" {
+		t.Errorf("toLatin1 = %q", got)
+	}
+}
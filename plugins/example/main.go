@@ -0,0 +1,97 @@
+// Plugin de referência do Focus Helper. Registra a ação LOG_EVENT, que grava
+// cada evento recebido como uma linha JSON em um arquivo.
+//
+// Compile e copie para um diretório de plugins:
+//
+//	go build -o focus_helper_plugins/log-event ./plugins/example
+//
+// Configuração da ação:
+//
+//	{Type: "LOG_EVENT", Plugin: map[string]any{"path": "./eventos.jsonl"}}
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+)
+
+type request struct {
+	ID     int64           `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type response struct {
+	JSONRPC string    `json:"jsonrpc"`
+	ID      int64     `json:"id"`
+	Result  any       `json:"result,omitempty"`
+	Error   *rpcError `json:"error,omitempty"`
+}
+
+type executeParams struct {
+	ActionType string          `json:"action_type"`
+	Event      json.RawMessage `json:"event"`
+	Config     struct {
+		Path string `json:"path"`
+	} `json:"config"`
+}
+
+func main() {
+	// A saída padrão é do protocolo; logs vão para a saída de erro.
+	log.SetOutput(os.Stderr)
+	scanner := bufio.NewScanner(os.Stdin)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	encoder := json.NewEncoder(os.Stdout)
+	for scanner.Scan() {
+		var req request
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			log.Printf("requisição inválida: %v", err)
+			continue
+		}
+		resp := response{JSONRPC: "2.0", ID: req.ID}
+		switch req.Method {
+		case "describe":
+			resp.Result = map[string]any{"name": "log-event", "action_types": []string{"LOG_EVENT"}}
+		case "execute":
+			output, err := execute(req.Params)
+			if err != nil {
+				resp.Error = &rpcError{Code: 1, Message: err.Error()}
+			} else {
+				resp.Result = map[string]any{"output": output}
+			}
+		default:
+			resp.Error = &rpcError{Code: -32601, Message: "método desconhecido: " + req.Method}
+		}
+		if err := encoder.Encode(resp); err != nil {
+			log.Fatalf("erro ao responder: %v", err)
+		}
+	}
+}
+
+func execute(raw json.RawMessage) (string, error) {
+	var params executeParams
+	if err := json.Unmarshal(raw, &params); err != nil {
+		return "", err
+	}
+	path := params.Config.Path
+	if path == "" {
+		path = "focus_helper_events.jsonl"
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if _, err := fmt.Fprintf(f, "%s\n", params.Event); err != nil {
+		return "", err
+	}
+	return "evento registrado em " + path, nil
+}
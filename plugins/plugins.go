@@ -0,0 +1,254 @@
+// Package plugins hospeda ações implementadas fora do processo. Um plugin é
+// qualquer executável em um diretório de plugins que fala JSON-RPC 2.0 pela
+// entrada e saída padrão, com uma mensagem JSON por linha.
+//
+// Ao iniciar, o Focus Helper chama o método "describe", que devolve os tipos
+// de ação atendidos pelo plugin. Cada disparo de uma dessas ações chama
+// "execute" com o evento do alerta e a configuração opaca da ação. O processo
+// fica vivo entre os alertas e é reiniciado se encerrar; uma chamada "execute"
+// interrompida pelo encerramento é repetida uma vez no processo reiniciado.
+package plugins
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DescribeResult é a resposta do método "describe".
+type DescribeResult struct {
+	Name        string   `json:"name"`
+	ActionTypes []string `json:"action_types"`
+}
+
+// ExecuteParams são os parâmetros do método "execute".
+type ExecuteParams struct {
+	ActionType string         `json:"action_type"`
+	Event      any            `json:"event"`
+	Config     map[string]any `json:"config,omitempty"`
+}
+
+// ExecuteResult é a resposta do método "execute".
+type ExecuteResult struct {
+	Output      string `json:"output,omitempty"`
+	ExitCode    int    `json:"exit_code,omitempty"`
+	Acknowledge bool   `json:"acknowledge,omitempty"` // encerra o alerta em andamento
+}
+
+// Plugin é um executável de plugin e o processo que o atende.
+type Plugin struct {
+	Path        string
+	Name        string
+	ActionTypes []string
+
+	mu    sync.Mutex
+	proc  *process      // nil enquanto o processo reinicia
+	ready chan struct{} // fechado quando proc volta a estar disponível
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]*Plugin)
+	running    sync.WaitGroup
+)
+
+// Start inicia os plugins encontrados nos diretórios e registra seus tipos de
+// ação. Diretórios inexistentes são ignorados. Os plugins são encerrados
+// quando ctx é cancelado; Wait aguarda o encerramento.
+func Start(ctx context.Context, dirs []string) {
+	for _, path := range findExecutables(dirs) {
+		p := &Plugin{Path: path}
+		proc, desc, err := p.launch(ctx)
+		if err != nil {
+			log.Printf("Erro ao iniciar plugin %s: %v", path, err)
+			continue
+		}
+		p.Name, p.ActionTypes, p.proc = desc.Name, desc.ActionTypes, proc
+		p.register()
+		running.Add(1)
+		go p.supervise(ctx)
+	}
+}
+
+// Wait aguarda o encerramento dos processos de plugin após o cancelamento do
+// contexto passado a Start.
+func Wait() {
+	running.Wait()
+}
+
+// Lookup retorna o plugin que atende o tipo de ação, ou nil.
+func Lookup(actionType string) *Plugin {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return registry[actionType]
+}
+
+// executeRetries é o número de novas tentativas quando o processo do plugin
+// encerra no meio de uma chamada "execute".
+const executeRetries = 1
+
+// Execute envia o evento ao plugin, aguardando o processo reiniciar se
+// necessário. Se o processo encerrar durante a chamada, ela é repetida no
+// processo reiniciado.
+func (p *Plugin) Execute(ctx context.Context, params ExecuteParams) (ExecuteResult, error) {
+	var result ExecuteResult
+	for attempt := 0; ; attempt++ {
+		proc, err := p.running(ctx)
+		if err != nil {
+			return result, err
+		}
+		err = proc.call(ctx, "execute", params, &result)
+		if errors.Is(err, errProcessExited) && attempt < executeRetries {
+			log.Printf("Plugin %s encerrou durante a chamada. Repetindo após o reinício.", p.Name)
+			p.markExited(proc)
+			continue
+		}
+		return result, err
+	}
+}
+
+// running aguarda um processo disponível. Um processo que já encerrou, mas
+// que o supervisor ainda não tirou de uso, é ignorado.
+func (p *Plugin) running(ctx context.Context) (*process, error) {
+	for {
+		p.mu.Lock()
+		proc, ready := p.proc, p.ready
+		p.mu.Unlock()
+		if proc != nil {
+			select {
+			case <-proc.done:
+				p.markExited(proc)
+				continue
+			default:
+				return proc, nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ready:
+		}
+	}
+}
+
+// markExited tira o processo encerrado de uso até o supervisor reiniciá-lo.
+// Tanto o supervisor quanto uma chamada interrompida podem notar o
+// encerramento primeiro; só o primeiro troca o canal ready.
+func (p *Plugin) markExited(proc *process) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.proc == proc {
+		p.proc, p.ready = nil, make(chan struct{})
+	}
+}
+
+func (p *Plugin) register() {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	for _, actionType := range p.ActionTypes {
+		if other, ok := registry[actionType]; ok {
+			log.Printf("Plugin %s: tipo de ação %s já registrado por %s. Ignorando.", p.Name, actionType, other.Name)
+			continue
+		}
+		registry[actionType] = p
+	}
+	log.Printf("Plugin %s carregado de %s: %v", p.Name, p.Path, p.ActionTypes)
+}
+
+// launch inicia o processo do plugin e consulta os tipos de ação atendidos.
+func (p *Plugin) launch(ctx context.Context) (*process, DescribeResult, error) {
+	var desc DescribeResult
+	proc, err := startProcess(p.Path)
+	if err != nil {
+		return nil, desc, err
+	}
+	describeCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	if err := proc.call(describeCtx, "describe", nil, &desc); err != nil {
+		proc.stop()
+		return nil, desc, fmt.Errorf("describe: %w", err)
+	}
+	if desc.Name == "" {
+		desc.Name = filepath.Base(p.Path)
+	}
+	return proc, desc, nil
+}
+
+// supervise reinicia o processo quando ele encerra, com espera crescente
+// entre as tentativas, até ctx ser cancelado.
+func (p *Plugin) supervise(ctx context.Context) {
+	defer running.Done()
+	backoff := time.Second
+	for {
+		p.mu.Lock()
+		proc := p.proc
+		p.mu.Unlock()
+		if proc != nil {
+			started := time.Now()
+			select {
+			case <-ctx.Done():
+				proc.stop()
+				return
+			case <-proc.done:
+			}
+			log.Printf("Plugin %s encerrou: %v. Reiniciando em %v.", p.Name, proc.err, backoff)
+			if time.Since(started) > time.Minute {
+				backoff = time.Second
+			}
+			p.markExited(proc)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, time.Minute)
+		// Os tipos de ação ficam os registrados na primeira inicialização.
+		next, _, err := p.launch(ctx)
+		if err != nil {
+			log.Printf("Erro ao reiniciar plugin %s: %v", p.Name, err)
+			continue
+		}
+		p.mu.Lock()
+		p.proc = next
+		close(p.ready)
+		p.mu.Unlock()
+	}
+}
+
+// findExecutables lista os arquivos executáveis dos diretórios de plugins.
+func findExecutables(dirs []string) []string {
+	var paths []string
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				log.Printf("Erro ao ler diretório de plugins %s: %v", dir, err)
+			}
+			continue
+		}
+		for _, entry := range entries {
+			info, err := entry.Info()
+			if err != nil || !info.Mode().IsRegular() || info.Mode().Perm()&0o111 == 0 {
+				continue
+			}
+			paths = append(paths, filepath.Join(dir, entry.Name()))
+		}
+	}
+	return paths
+}
+
+// rawParams converte os parâmetros da chamada para JSON.
+func rawParams(params any) (json.RawMessage, error) {
+	if params == nil {
+		return nil, nil
+	}
+	return json.Marshal(params)
+}
@@ -0,0 +1,217 @@
+package plugins
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// buildExample compila o plugin de referência em um diretório de plugins temporário.
+func buildExample(t *testing.T) string {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("compila o plugin de exemplo")
+	}
+	dir := t.TempDir()
+	cmd := exec.Command("go", "build", "-o", filepath.Join(dir, "log-event"), "./example")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("go build ./example: %v\n%s", err, out)
+	}
+	return dir
+}
+
+// startPlugins inicia os plugins de dir e os encerra no fim do teste,
+// limpando o registro global.
+func startPlugins(t *testing.T, dir string) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	Start(ctx, []string{dir})
+	t.Cleanup(func() {
+		cancel()
+		Wait()
+		registryMu.Lock()
+		clear(registry)
+		registryMu.Unlock()
+	})
+}
+
+func (p *Plugin) currentProcess() *process {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.proc
+}
+
+func TestExamplePlugin(t *testing.T) {
+	startPlugins(t, buildExample(t))
+
+	p := Lookup("LOG_EVENT")
+	if p == nil {
+		t.Fatal("LOG_EVENT não foi registrado pelo describe")
+	}
+	if p.Name != "log-event" || !slices.Equal(p.ActionTypes, []string{"LOG_EVENT"}) {
+		t.Errorf("describe = %q %v", p.Name, p.ActionTypes)
+	}
+
+	path := filepath.Join(t.TempDir(), "eventos.jsonl")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for _, level := range []string{"HIGH", "CRITICAL"} {
+		result, err := p.Execute(ctx, ExecuteParams{
+			ActionType: "LOG_EVENT",
+			Event:      map[string]any{"level": level, "session_minutes": 95},
+			Config:     map[string]any{"path": path},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := "evento registrado em " + path; result.Output != want {
+			t.Errorf("output %q, esperado %q", result.Output, want)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("%d eventos gravados, esperado 2:\n%s", len(lines), data)
+	}
+	for i, level := range []string{"HIGH", "CRITICAL"} {
+		var event map[string]any
+		if err := json.Unmarshal([]byte(lines[i]), &event); err != nil || event["level"] != level {
+			t.Errorf("evento %d = %s (%v)", i, lines[i], err)
+		}
+	}
+
+	// Um diretório no lugar do arquivo faz o plugin responder com erro.
+	_, err = p.Execute(ctx, ExecuteParams{ActionType: "LOG_EVENT", Config: map[string]any{"path": t.TempDir()}})
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != 1 {
+		t.Errorf("erro %v, esperado RPCError do plugin", err)
+	}
+}
+
+func TestPluginRestartsAfterCrash(t *testing.T) {
+	startPlugins(t, buildExample(t))
+	p := Lookup("LOG_EVENT")
+	if p == nil {
+		t.Fatal("LOG_EVENT não foi registrado")
+	}
+	crashed := p.currentProcess()
+	if err := crashed.cmd.Process.Kill(); err != nil {
+		t.Fatal(err)
+	}
+	<-crashed.done
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	path := filepath.Join(t.TempDir(), "eventos.jsonl")
+	if _, err := p.Execute(ctx, ExecuteParams{ActionType: "LOG_EVENT", Config: map[string]any{"path": path}}); err != nil {
+		t.Fatalf("execute após o reinício: %v", err)
+	}
+	if restarted := p.currentProcess(); restarted == nil || restarted == crashed {
+		t.Error("o processo do plugin não foi reiniciado")
+	}
+}
+
+// crashingPlugin encerra na primeira chamada "execute" e responde normalmente
+// depois de reiniciado, quando o arquivo marker já existe.
+const crashingPlugin = `#!/bin/sh
+reply() {
+	id=$(echo "$1" | sed 's/.*"id":\([0-9]*\).*/\1/')
+	echo "{\"jsonrpc\":\"2.0\",\"id\":$id,\"result\":$2}"
+}
+while read -r line; do
+	case "$line" in
+	*'"describe"'*) reply "$line" '{"name":"crash","action_types":["CRASH"]}' ;;
+	*'"execute"'*)
+		if [ ! -e "%MARKER%" ]; then
+			touch "%MARKER%"
+			exit 3
+		fi
+		reply "$line" '{"output":"ok"}' ;;
+	esac
+done
+`
+
+func TestExecuteRetriesWhenPluginExitsMidCall(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh não encontrado")
+	}
+	dir := t.TempDir()
+	marker := filepath.Join(t.TempDir(), "crashed")
+	script := strings.ReplaceAll(crashingPlugin, "%MARKER%", marker)
+	if err := os.WriteFile(filepath.Join(dir, "crash"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	startPlugins(t, dir)
+	p := Lookup("CRASH")
+	if p == nil {
+		t.Fatal("CRASH não foi registrado")
+	}
+	first := p.currentProcess()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	result, err := p.Execute(ctx, ExecuteParams{ActionType: "CRASH"})
+	if err != nil {
+		t.Fatalf("execute com o plugin encerrando no meio da chamada: %v", err)
+	}
+	if result.Output != "ok" {
+		t.Errorf("output %q, esperado a resposta do processo reiniciado", result.Output)
+	}
+	if _, err := os.Stat(marker); err != nil {
+		t.Error("o plugin não chegou a encerrar na primeira chamada")
+	}
+	if p.currentProcess() == first {
+		t.Error("a chamada repetida usou o processo encerrado")
+	}
+}
+
+func TestExecuteGivesUpAfterRetry(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh não encontrado")
+	}
+	dir := t.TempDir()
+	// Sem marker criável, o plugin encerra em toda chamada "execute".
+	script := strings.ReplaceAll(crashingPlugin, "%MARKER%", "/dev/null/impossivel")
+	if err := os.WriteFile(filepath.Join(dir, "crash"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	startPlugins(t, dir)
+	p := Lookup("CRASH")
+	if p == nil {
+		t.Fatal("CRASH não foi registrado")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := p.Execute(ctx, ExecuteParams{ActionType: "CRASH"}); !errors.Is(err, errProcessExited) {
+		t.Errorf("erro %v, esperado %v após a nova tentativa", err, errProcessExited)
+	}
+}
+
+func TestExecuteCanceledWhileRestarting(t *testing.T) {
+	startPlugins(t, buildExample(t))
+	p := Lookup("LOG_EVENT")
+	if p == nil {
+		t.Fatal("LOG_EVENT não foi registrado")
+	}
+	proc := p.currentProcess()
+	proc.cmd.Process.Kill()
+	<-proc.done
+	p.markExited(proc)
+
+	// O supervisor espera pelo menos um segundo antes de reiniciar.
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if _, err := p.Execute(ctx, ExecuteParams{ActionType: "LOG_EVENT"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("erro %v, esperado o prazo do contexto", err)
+	}
+}
@@ -0,0 +1,187 @@
+package plugins
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// RPCError é um erro JSON-RPC devolvido pelo plugin.
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("erro do plugin (%d): %s", e.Code, e.Message)
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      int64           `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      int64           `json:"id"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+var errProcessExited = errors.New("processo do plugin encerrado")
+
+// process é uma instância em execução de um plugin.
+type process struct {
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+
+	writeMu sync.Mutex
+	mu      sync.Mutex
+	nextID  int64
+	pending map[int64]chan response
+
+	stderrDone chan struct{}
+	done       chan struct{} // fechado quando o processo encerra
+	err        error         // motivo do encerramento, válido após done
+}
+
+func startProcess(path string) (*process, error) {
+	cmd := exec.Command(path)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	proc := &process{
+		cmd:        cmd,
+		stdin:      stdin,
+		pending:    make(map[int64]chan response),
+		stderrDone: make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	go proc.logStderr(filepath.Base(path), stderr)
+	go proc.readResponses(stdout)
+	return proc, nil
+}
+
+// call envia uma requisição e aguarda a resposta correspondente.
+func (p *process) call(ctx context.Context, method string, params, result any) error {
+	raw, err := rawParams(params)
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	p.nextID++
+	id := p.nextID
+	ch := make(chan response, 1)
+	p.pending[id] = ch
+	p.mu.Unlock()
+	defer func() {
+		p.mu.Lock()
+		delete(p.pending, id)
+		p.mu.Unlock()
+	}()
+
+	line, err := json.Marshal(request{JSONRPC: "2.0", ID: id, Method: method, Params: raw})
+	if err != nil {
+		return err
+	}
+	p.writeMu.Lock()
+	_, err = p.stdin.Write(append(line, '\n'))
+	p.writeMu.Unlock()
+	if err != nil {
+		// A escrita falha quando o processo encerrou; nesse caso o erro é o
+		// encerramento, para a chamada poder ser repetida após o reinício.
+		select {
+		case <-p.done:
+			return errProcessExited
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+			return fmt.Errorf("erro ao enviar requisição ao plugin: %w", err)
+		}
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-p.done:
+		return errProcessExited
+	case resp := <-ch:
+		if resp.Error != nil {
+			return resp.Error
+		}
+		if result != nil && len(resp.Result) > 0 {
+			return json.Unmarshal(resp.Result, result)
+		}
+		return nil
+	}
+}
+
+// readResponses entrega cada resposta à chamada pendente com o mesmo ID.
+func (p *process) readResponses(stdout io.Reader) {
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var resp response
+		if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+			log.Printf("Plugin %s: resposta inválida ignorada: %v", p.cmd.Path, err)
+			continue
+		}
+		p.mu.Lock()
+		ch, ok := p.pending[resp.ID]
+		p.mu.Unlock()
+		if ok {
+			select {
+			case ch <- resp:
+			default:
+			}
+		}
+	}
+	<-p.stderrDone
+	p.err = p.cmd.Wait()
+	if p.err == nil {
+		p.err = errProcessExited
+	}
+	close(p.done)
+}
+
+func (p *process) logStderr(name string, stderr io.Reader) {
+	defer close(p.stderrDone)
+	scanner := bufio.NewScanner(stderr)
+	for scanner.Scan() {
+		log.Printf("[plugin %s] %s", name, scanner.Text())
+	}
+}
+
+// stop fecha a entrada padrão, sinal para o plugin encerrar, e força o
+// encerramento se ele não sair a tempo.
+func (p *process) stop() {
+	p.stdin.Close()
+	select {
+	case <-p.done:
+	case <-time.After(3 * time.Second):
+		p.cmd.Process.Kill()
+		<-p.done
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/brutalzinn/focus-helper/config"
+	"github.com/brutalzinn/focus-helper/database"
+	"github.com/brutalzinn/focus-helper/status"
+)
+
+// pomodoroLoop executa ciclos fixos de trabalho e pausa pelo mesmo pipeline
+// de ações dos alertas de hiperfoco. Retorna quando ctx é cancelado; o ciclo
+// interrompido não é gravado.
+func pomodoroLoop(ctx context.Context) {
+	cfg := config.AppConfig.Pomodoro
+	for cycle := 1; ; cycle++ {
+		startedAt := time.Now()
+		log.Printf("Pomodoro: ciclo %d iniciado (%v de trabalho).", cycle, cfg.WorkDuration)
+		work, endWork := context.WithTimeout(ctx, cfg.WorkDuration)
+		runPomodoroPhase(work, cfg.WorkStart, 0)
+		waitPomodoroWork(work, pomodoroPhase{cycle: cycle, name: status.PhaseWork, start: startedAt, end: startedAt.Add(cfg.WorkDuration)})
+		endWork()
+		if ctx.Err() != nil {
+			return
+		}
+
+		breakDuration, phaseName := cfg.ShortBreak, status.PhaseBreak
+		longBreak := cfg.CyclesBeforeLongBreak > 0 && cycle%cfg.CyclesBeforeLongBreak == 0
+		if longBreak {
+			breakDuration, phaseName = cfg.LongBreak, status.PhaseLongBreak
+		}
+		log.Printf("Pomodoro: pausa de %v iniciada.", breakDuration)
+		breakStart := time.Now()
+		pause, endPause := context.WithTimeout(ctx, breakDuration)
+		runPomodoroPhase(pause, cfg.BreakStart, cfg.WorkDuration)
+		violations := watchPomodoroBreak(ctx, pomodoroPhase{cycle: cycle, name: phaseName, start: breakStart, end: breakStart.Add(breakDuration)})
+		endPause()
+		if ctx.Err() != nil {
+			return
+		}
+
+		database.LogPomodoroCycle(db, database.PomodoroCycle{
+			StartedAt:       startedAt,
+			CompletedAt:     time.Now(),
+			Work:            cfg.WorkDuration,
+			Break:           breakDuration,
+			LongBreak:       longBreak,
+			BreakViolations: violations,
+		})
+		log.Printf("Pomodoro: ciclo %d concluído (%d violações de pausa).", cycle, violations)
+	}
+}
+
+// pomodoroPhase é a fase em andamento, gravada no arquivo de status.
+type pomodoroPhase struct {
+	cycle      int
+	name       string // status.PhaseWork, status.PhaseBreak ou status.PhaseLongBreak
+	start, end time.Time
+}
+
+// writePomodoroStatus grava o status com o modo pomodoro e a fase atual.
+func writePomodoroStatus(phase pomodoroPhase) {
+	now := time.Now()
+	end := phase.end
+	st := status.Status{
+		UpdatedAt:      now,
+		Mode:           status.ModePomodoro,
+		Phase:          phase.name,
+		Cycle:          phase.cycle,
+		PhaseEndsAt:    &end,
+		SessionSeconds: int64(now.Sub(phase.start).Seconds()),
+	}
+	if until := alerts.snoozedUntilTime(); until.After(now) {
+		st.SnoozedUntil = &until
+	}
+	if err := status.Write(config.AppConfig.StatusFile, st); err != nil {
+		log.Printf("Erro ao gravar arquivo de status: %v", err)
+	}
+	stateReport.report(st)
+}
+
+// waitPomodoroWork aguarda o fim da fase de trabalho, gravando o status a
+// cada verificação de atividade.
+func waitPomodoroWork(work context.Context, phase pomodoroPhase) {
+	ticker := time.NewTicker(config.AppConfig.ActivityCheckRate)
+	defer ticker.Stop()
+	for {
+		writePomodoroStatus(phase)
+		select {
+		case <-work.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runPomodoroPhase anuncia o início de uma fase. Se o limitador adiar o
+// anúncio, ele é tentado de novo até o fim da fase (phase).
+func runPomodoroPhase(phase context.Context, level config.AlertLevel, session time.Duration) {
+	state := &config.HyperfocusState{Level: level.Level, StartTime: time.Now()}
+	alerts.fireWhenAdmitted(phase, level, state, session)
+}
+
+// watchPomodoroBreak verifica a atividade durante a pausa e aciona a torre
+// quando o usuário continua nos controles. Retorna o número de violações.
+func watchPomodoroBreak(ctx context.Context, phase pomodoroPhase) int {
+	cfg := config.AppConfig.Pomodoro
+	ticker := time.NewTicker(config.AppConfig.ActivityCheckRate)
+	defer ticker.Stop()
+
+	start, end := phase.start, phase.end
+	activityMonitor.HasActivity() // descarta o movimento anterior à pausa
+	writePomodoroStatus(phase)
+	violations := 0
+	var lastCallout time.Time
+	pending := false // a última chamada foi adiada pelo limitador
+	for {
+		var now time.Time
+		select {
+		case <-ctx.Done():
+			return violations
+		case now = <-ticker.C:
+		}
+		if now.After(end) {
+			return violations
+		}
+		writePomodoroStatus(phase)
+		if !pending {
+			if !activityMonitor.HasActivity() || now.Sub(start) < cfg.BreakGrace {
+				continue
+			}
+			if now.Sub(lastCallout) < cfg.CalloutInterval {
+				continue
+			}
+			violations++
+			lastCallout = now
+			log.Printf("Pomodoro: atividade detectada durante a pausa (violação %d).", violations)
+		}
+		// Adiada pelo limitador, a chamada é tentada de novo na próxima verificação.
+		state := &config.HyperfocusState{Level: cfg.BreakViolation.Level, StartTime: now}
+		pending = !alerts.fire(cfg.BreakViolation, state, cfg.WorkDuration).delivered()
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/brutalzinn/focus-helper/activity"
+	"github.com/brutalzinn/focus-helper/config"
+	"github.com/brutalzinn/focus-helper/status"
+)
+
+func TestPomodoroLoopStopsOnShutdown(t *testing.T) {
+	tests := []struct {
+		name       string
+		work       time.Duration
+		pause      time.Duration
+		wantCycles int
+	}{
+		{"durante o trabalho", time.Hour, time.Hour, 0},
+		{"durante a pausa", 20 * time.Millisecond, time.Hour, 0},
+		{"depois de um ciclo", 10 * time.Millisecond, 30 * time.Millisecond, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			openTestDB(t)
+			config.AppConfig.ActivityCheckRate = 10 * time.Millisecond
+			config.AppConfig.StatusFile = filepath.Join(t.TempDir(), "status.json")
+			config.AppConfig.Pomodoro = config.PomodoroConfig{WorkDuration: tt.work, ShortBreak: tt.pause}
+			config.AppConfig.RateLimit = config.RateLimitConfig{}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			alerts = newAlertManager(ctx)
+			activityMonitor = activity.NewMonitor()
+
+			done := make(chan struct{})
+			go func() {
+				pomodoroLoop(ctx)
+				close(done)
+			}()
+			time.Sleep(100 * time.Millisecond)
+			cancel()
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("pomodoroLoop não retornou depois do cancelamento")
+			}
+
+			var cycles int
+			if err := db.QueryRow("SELECT COUNT(*) FROM pomodoro_cycles").Scan(&cycles); err != nil {
+				t.Fatal(err)
+			}
+			// O ciclo interrompido nunca é gravado.
+			if tt.wantCycles == 0 && cycles != 0 || tt.wantCycles > 0 && cycles < tt.wantCycles {
+				t.Errorf("%d ciclos gravados, esperado %d", cycles, tt.wantCycles)
+			}
+		})
+	}
+}
+
+func TestPomodoroLoopWritesStatus(t *testing.T) {
+	openTestDB(t)
+	config.AppConfig.ActivityCheckRate = 10 * time.Millisecond
+	config.AppConfig.StatusFile = filepath.Join(t.TempDir(), "status.json")
+	config.AppConfig.Pomodoro = config.PomodoroConfig{WorkDuration: 50 * time.Millisecond, ShortBreak: time.Hour}
+	config.AppConfig.RateLimit = config.RateLimitConfig{}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	alerts = newAlertManager(ctx)
+	activityMonitor = activity.NewMonitor()
+	done := make(chan struct{})
+	go func() {
+		pomodoroLoop(ctx)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	waitPhase := func(want string) status.Status {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for {
+			st, err := status.Read(config.AppConfig.StatusFile)
+			if err == nil && st.Phase == want {
+				return st
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("fase %q não gravada; último status %+v (%v)", want, st, err)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+	work := waitPhase(status.PhaseWork)
+	if work.Mode != status.ModePomodoro || work.Cycle != 1 || work.PhaseEndsAt == nil {
+		t.Errorf("status do trabalho = %+v", work)
+	}
+	pause := waitPhase(status.PhaseBreak)
+	if pause.Mode != status.ModePomodoro || pause.Cycle != 1 || pause.PhaseEndsAt == nil || time.Until(*pause.PhaseEndsAt) < 50*time.Minute {
+		t.Errorf("status da pausa = %+v", pause)
+	}
+}
@@ -0,0 +1,148 @@
+package status
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Modos do daemon e fases do pomodoro gravados no status.
+const (
+	ModeHyperfocus = "hyperfocus"
+	ModePomodoro   = "pomodoro"
+
+	PhaseWork      = "work"
+	PhaseBreak     = "break"
+	PhaseLongBreak = "long_break"
+)
+
+// Status é o retrato do daemon gravado a cada verificação de atividade, para
+// que barras de status e a CLI possam consultar o estado atual.
+type Status struct {
+	UpdatedAt      time.Time  `json:"updated_at"`
+	Mode           string     `json:"mode"`
+	Idle           bool       `json:"idle"`
+	Level          string     `json:"level,omitempty"`
+	Task           string     `json:"task,omitempty"`
+	SessionSeconds int64      `json:"session_seconds"`
+	DailySeconds   int64      `json:"daily_seconds,omitempty"`
+	NextLevel      string     `json:"next_level,omitempty"`
+	NextLevelAt    *time.Time `json:"next_level_at,omitempty"`
+	SnoozedUntil   *time.Time `json:"snoozed_until,omitempty"`
+	Phase          string     `json:"phase,omitempty"`         // fase do pomodoro
+	Cycle          int        `json:"cycle,omitempty"`         // ciclo do pomodoro, a partir de 1
+	PhaseEndsAt    *time.Time `json:"phase_ends_at,omitempty"` // fim da fase do pomodoro
+}
+
+// Write grava o status de forma atômica.
+func Write(path string, st Status) error {
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".status-*.json")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// Read lê o último status gravado pelo daemon.
+func Read(path string) (Status, error) {
+	var st Status
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return st, err
+	}
+	err = json.Unmarshal(data, &st)
+	return st, err
+}
+
+// UntilNextLevel retorna quanto falta para o próximo nível de alerta.
+func (st Status) UntilNextLevel(now time.Time) (time.Duration, bool) {
+	if st.NextLevel == "" || st.NextLevelAt == nil || st.Idle {
+		return 0, false
+	}
+	remaining := st.NextLevelAt.Sub(now)
+	if remaining < 0 {
+		remaining = 0
+	}
+	return remaining, true
+}
+
+// Summary formata o status em uma linha, adequada para barras de status.
+func (st Status) Summary(now time.Time) string {
+	if st.Mode == ModePomodoro {
+		return st.pomodoroSummary(now)
+	}
+	parts := []string{fmt.Sprintf("sessão %s", formatClock(time.Duration(st.SessionSeconds)*time.Second))}
+	if st.Idle {
+		parts = []string{"em pausa"}
+	}
+	if st.Level != "" {
+		parts = append(parts, "nível "+st.Level)
+	}
+	if remaining, ok := st.UntilNextLevel(now); ok {
+		parts = append(parts, fmt.Sprintf("%s em %s", st.NextLevel, formatClock(remaining)))
+	}
+	if st.Task != "" {
+		parts = append(parts, fmt.Sprintf("plano: %s", st.Task))
+	}
+	if st.Snoozed(now) {
+		parts = append(parts, fmt.Sprintf("soneca por %s", formatClock(st.SnoozedUntil.Sub(now))))
+	}
+	return strings.Join(parts, " | ")
+}
+
+// pomodoroSummary formata a fase e o ciclo do pomodoro e quanto falta para o
+// fim da fase.
+func (st Status) pomodoroSummary(now time.Time) string {
+	phase := map[string]string{PhaseWork: "trabalho", PhaseBreak: "pausa", PhaseLongBreak: "pausa longa"}[st.Phase]
+	if phase == "" {
+		phase = st.Phase
+	}
+	parts := []string{fmt.Sprintf("pomodoro %d: %s", st.Cycle, phase)}
+	if st.PhaseEndsAt != nil {
+		remaining := st.PhaseEndsAt.Sub(now)
+		if remaining < 0 {
+			remaining = 0
+		}
+		parts = append(parts, fmt.Sprintf("termina em %s", formatClock(remaining)))
+	}
+	if st.Snoozed(now) {
+		parts = append(parts, fmt.Sprintf("soneca por %s", formatClock(st.SnoozedUntil.Sub(now))))
+	}
+	return strings.Join(parts, " | ")
+}
+
+// Snoozed indica se os alertas estão adiados por soneca em now.
+func (st Status) Snoozed(now time.Time) bool {
+	return st.SnoozedUntil != nil && st.SnoozedUntil.After(now)
+}
+
+func formatClock(d time.Duration) string {
+	d = d.Round(time.Second)
+	h := int(d.Hours())
+	m := int(d.Minutes()) % 60
+	s := int(d.Seconds()) % 60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%02d:%02d", m, s)
+}
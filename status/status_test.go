@@ -0,0 +1,79 @@
+package status
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteOmitsUnsetTimes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "status.json")
+	now := time.Date(2026, 10, 19, 14, 0, 0, 0, time.UTC)
+	if err := Write(path, Status{UpdatedAt: now, Mode: "hyperfocus", SessionSeconds: 60}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"next_level_at", "snoozed_until"} {
+		if strings.Contains(string(data), key) {
+			t.Errorf("%s gravado sem valor:\n%s", key, data)
+		}
+	}
+
+	next, snoozed := now.Add(10*time.Minute), now.Add(5*time.Minute)
+	want := Status{UpdatedAt: now, Mode: "hyperfocus", NextLevel: "HIGH", NextLevelAt: &next, SnoozedUntil: &snoozed}
+	if err := Write(path, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.NextLevelAt == nil || !got.NextLevelAt.Equal(next) || got.SnoozedUntil == nil || !got.SnoozedUntil.Equal(snoozed) {
+		t.Errorf("status lido = %+v", got)
+	}
+}
+
+func TestSummary(t *testing.T) {
+	now := time.Date(2026, 10, 19, 14, 0, 0, 0, time.UTC)
+	next, snoozed, past := now.Add(90*time.Second), now.Add(5*time.Minute), now.Add(-time.Minute)
+	tests := []struct {
+		name string
+		st   Status
+		want string
+	}{
+		{"sem próximo nível", Status{SessionSeconds: 65}, "sessão 01:05"},
+		{"próximo nível e soneca", Status{SessionSeconds: 3600, Level: "LOW", NextLevel: "MEDIUM", NextLevelAt: &next, Task: "relatório", SnoozedUntil: &snoozed},
+			"sessão 1:00:00 | nível LOW | MEDIUM em 01:30 | plano: relatório | soneca por 05:00"},
+		{"soneca vencida", Status{SessionSeconds: 0, SnoozedUntil: &past}, "sessão 00:00"},
+		{"ocioso", Status{Idle: true, NextLevel: "MEDIUM", NextLevelAt: &next}, "em pausa"},
+		{"pomodoro no trabalho", Status{Mode: ModePomodoro, Phase: PhaseWork, Cycle: 2, PhaseEndsAt: &next}, "pomodoro 2: trabalho | termina em 01:30"},
+		{"pomodoro na pausa longa", Status{Mode: ModePomodoro, Phase: PhaseLongBreak, Cycle: 4, PhaseEndsAt: &past, SnoozedUntil: &snoozed},
+			"pomodoro 4: pausa longa | termina em 00:00 | soneca por 05:00"},
+	}
+	for _, tt := range tests {
+		if got := tt.st.Summary(now); got != tt.want {
+			t.Errorf("%s: %q, esperado %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWriteRemovesTempFileOnFailure(t *testing.T) {
+	dir := t.TempDir()
+	// O destino é um diretório não vazio, então o rename falha.
+	path := filepath.Join(dir, "status.json")
+	if err := os.MkdirAll(filepath.Join(path, "ocupado"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := Write(path, Status{Mode: ModeHyperfocus}); err == nil {
+		t.Fatal("Write deveria falhar")
+	}
+	leftovers, _ := filepath.Glob(filepath.Join(dir, ".status-*.json"))
+	if len(leftovers) > 0 {
+		t.Errorf("arquivos temporários restantes: %v", leftovers)
+	}
+}
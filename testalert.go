@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/brutalzinn/focus-helper/actions"
+	"github.com/brutalzinn/focus-helper/audio"
+	"github.com/brutalzinn/focus-helper/config"
+	"github.com/brutalzinn/focus-helper/database"
+	"github.com/brutalzinn/focus-helper/plugins"
+)
+
+// runTestAlertCommand dispara imediatamente as ações de um nível de alerta,
+// ou apenas as descreve com --dry-run.
+func runTestAlertCommand(args []string) {
+	fs := flag.NewFlagSet("test-alert", flag.ExitOnError)
+	debugFlag := fs.Bool("debug", false, "Use the debug configuration")
+	levelName := fs.String("level", "", "Alert level to run, e.g. HIGH")
+	dryRun := fs.Bool("dry-run", false, "Print the resolved actions without running them")
+	session := fs.Duration("session", 0, "Session duration to simulate (default: the level threshold)")
+	fs.Parse(args)
+
+	config.Init(*debugFlag)
+	appConfig = config.AppConfig
+	level, ok := findAlertLevel(*levelName)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "uso: focus-helper test-alert --level NÍVEL [--dry-run] [--session 2h]\nníveis disponíveis: %s\n",
+			strings.Join(alertLevelNames(), ", "))
+		os.Exit(2)
+	}
+	if *session <= 0 {
+		*session = level.Threshold
+	}
+
+	var err error
+	db, err = database.Init(appConfig.DatabaseFile)
+	if err != nil {
+		log.Fatalf("Falha ao inicializar banco de dados: %v", err)
+	}
+	defer db.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	lastAnswer, err := database.GetLastWellbeingAnswer(db)
+	if err != nil {
+		log.Printf("Erro ao consultar última resposta de bem-estar: %v", err)
+	}
+	state := &config.HyperfocusState{Level: level.Level, StartTime: time.Now().Add(-*session)}
+	if plan, err := database.GetActiveFlightPlan(db); err == nil && plan != nil {
+		state.Task = plan.Task
+	}
+	event := actions.Event{
+		Level:               level,
+		State:               state,
+		MaxRepeats:          appConfig.RateLimit.RepeatCap(),
+		Acknowledge:         func() { cancel(errAlertAcknowledged) },
+		SessionDuration:     *session,
+		LastWellbeingAnswer: lastAnswer,
+		Shutdown:            ctx.Done(),
+	}
+
+	if *dryRun {
+		actions.DryRun(os.Stdout, event)
+		return
+	}
+	// Os plugins só sobem quando as ações vão rodar; o --dry-run não inicia
+	// processos externos. Ao sair, o contexto é cancelado antes de aguardar
+	// o encerramento dos plugins.
+	plugins.Start(ctx, appConfig.PluginDirs)
+	defer func() {
+		cancel(nil)
+		plugins.Wait()
+	}()
+	audio.InitSpeaker()
+	actions.ExecuteAndWait(ctx, event)
+}
+
+// findAlertLevel procura o nível pelo nome, sem diferenciar maiúsculas, entre
+// todos os níveis configurados.
+func findAlertLevel(name string) (config.AlertLevel, bool) {
+	for _, level := range allAlertLevels() {
+		if name != "" && strings.EqualFold(level.Level, name) {
+			return level, true
+		}
+	}
+	return config.AlertLevel{}, false
+}
+
+func allAlertLevels() []config.AlertLevel {
+	cfg := config.AppConfig
+	levels := append([]config.AlertLevel{}, cfg.AlertLevels...)
+	levels = append(levels, cfg.DailyBudget.AlertLevels...)
+	levels = append(levels, cfg.Pomodoro.WorkStart, cfg.Pomodoro.BreakStart, cfg.Pomodoro.BreakViolation, cfg.Escalation.Alert)
+	return levels
+}
+
+func alertLevelNames() []string {
+	var names []string
+	for _, level := range allAlertLevels() {
+		if level.Level != "" {
+			names = append(names, level.Level)
+		}
+	}
+	return names
+}